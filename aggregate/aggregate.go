@@ -0,0 +1,136 @@
+// Package aggregate rolls up postcodes by a level of the postcode hierarchy (area, district, sector, ...) or of the
+// administrative and statistical geographies (LSOA, ward, region, ...), with counts and centroids.
+package aggregate
+
+import (
+	"math"
+	"sort"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/geo"
+)
+
+const (
+	degToRad = math.Pi / 180
+	// precision is the scale centroids are rounded to, the 6 decimal places of the coordinates of the API.
+	precision = 1e6
+)
+
+// Group is the aggregate of the postcodes sharing a key at a level.
+// Geocoded is the number of postcodes with coordinates, and Centroid, valid only if Geocoded is positive, is their
+// geographic mean, rounded to 6 decimal places.
+type Group struct {
+	Key      string
+	Count    int
+	Geocoded int
+	Centroid geo.Point
+}
+
+// Aggregator groups postcodes by a level as they are added. It is not safe for concurrent use.
+type Aggregator struct {
+	level  Level
+	groups map[string]*group
+}
+
+// group accumulates a Group, with the sum of the unit vectors of its coordinates.
+type group struct {
+	Group
+	x, y, z float64
+}
+
+// New returns an Aggregator grouping postcodes by level.
+func New(level Level) *Aggregator {
+	return &Aggregator{level: level, groups: map[string]*group{}}
+}
+
+// By groups postcodes by level.
+func By(level Level, postcodes []postcodesio.Postcode) *Aggregator {
+	a := New(level)
+
+	for _, p := range postcodes {
+		a.Add(p)
+	}
+
+	return a
+}
+
+// Level returns the level the Aggregator groups postcodes by.
+func (a *Aggregator) Level() Level {
+	return a.level
+}
+
+// Add adds a postcode to its group. Postcodes without a value for the level are grouped under the empty key.
+func (a *Aggregator) Add(p postcodesio.Postcode) {
+	key := a.level.Key(p)
+
+	g, ok := a.groups[key]
+	if !ok {
+		g = &group{Group: Group{Key: key}}
+		a.groups[key] = g
+	}
+
+	g.Count++
+
+	lat, lon, ok := p.Location()
+	if !ok {
+		return
+	}
+
+	g.Geocoded++
+
+	phi, lambda := lat*degToRad, lon*degToRad
+	g.x += math.Cos(phi) * math.Cos(lambda)
+	g.y += math.Cos(phi) * math.Sin(lambda)
+	g.z += math.Sin(phi)
+}
+
+// AddStream adds the postcodes found by a lookup stream, e.g. postcodesio.Client.LookupStream, until in is closed.
+// It returns the number of postcodes not found and the first lookup error, after consuming the whole stream.
+func (a *Aggregator) AddStream(in <-chan postcodesio.LookupResult) (int, error) {
+	var (
+		notFound int
+		err      error
+	)
+
+	for r := range in {
+		switch {
+		case r.Err != nil:
+			if err == nil {
+				err = r.Err
+			}
+		case r.Result == nil:
+			notFound++
+		default:
+			a.Add(*r.Result)
+		}
+	}
+
+	return notFound, err
+}
+
+// Groups returns the groups sorted by key.
+func (a *Aggregator) Groups() []Group {
+	groups := make([]Group, 0, len(a.groups))
+
+	for _, g := range a.groups {
+		result := g.Group
+
+		if g.Geocoded > 0 {
+			result.Centroid = geo.Point{
+				Latitude:  round(math.Atan2(g.z, math.Hypot(g.x, g.y)) / degToRad),
+				Longitude: round(math.Atan2(g.y, g.x) / degToRad),
+			}
+		}
+
+		groups = append(groups, result)
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+
+	return groups
+}
+
+// round rounds a coordinate to the precision of the API.
+func round(deg float64) float64 {
+	return math.Round(deg*precision) / precision
+}
@@ -0,0 +1,115 @@
+package aggregate_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/aggregate"
+	"github.com/stretchr/testify/assert"
+)
+
+func postcode(pc string, lat, lon float64, lsoa string) postcodesio.Postcode {
+	parts, _ := postcodesio.ParsePostcode(pc)
+
+	return postcodesio.Postcode{
+		Postcode:  pc,
+		Outcode:   parts.Outcode,
+		Latitude:  postcodesio.Some(lat),
+		Longitude: postcodesio.Some(lon),
+		LSOA:      postcodesio.Some(lsoa),
+		Codes:     postcodesio.Codes{LSOA: "E0" + lsoa},
+	}
+}
+
+var testPostcodes = []postcodesio.Postcode{
+	postcode("SW1A 2AA", 51.5, -0.1, "Westminster 018C"),
+	postcode("SW1A 2AB", 51.6, -0.1, "Westminster 018C"),
+	postcode("SW1A 1AA", 51.5, -0.2, "Westminster 018A"),
+	postcode("NW1 6XE", 51.52, -0.16, "Westminster 008B"),
+	{Postcode: "GY1 1AA", Outcode: "GY1"},
+}
+
+func TestBy(t *testing.T) {
+	tests := []struct {
+		level    aggregate.Level
+		expected map[string]int
+	}{
+		{aggregate.Area, map[string]int{"SW": 3, "NW": 1, "GY": 1}},
+		{aggregate.District, map[string]int{"SW1A": 3, "NW1": 1, "GY1": 1}},
+		{aggregate.Outcode, map[string]int{"SW1A": 3, "NW1": 1, "GY1": 1}},
+		{aggregate.Sector, map[string]int{"SW1A 2": 2, "SW1A 1": 1, "NW1 6": 1, "GY1 1": 1}},
+		{aggregate.LSOA, map[string]int{"Westminster 018C": 2, "Westminster 018A": 1, "Westminster 008B": 1, "": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.Name, func(t *testing.T) {
+			counts := map[string]int{}
+			for _, g := range aggregate.By(tt.level, testPostcodes).Groups() {
+				counts[g.Key] = g.Count
+			}
+
+			assert.Equal(t, tt.expected, counts)
+		})
+	}
+}
+
+func TestGroups_Centroid(t *testing.T) {
+	groups := aggregate.By(aggregate.Sector, testPostcodes).Groups()
+	assert.Equal(t, []string{"GY1 1", "NW1 6", "SW1A 1", "SW1A 2"}, []string{groups[0].Key, groups[1].Key, groups[2].Key, groups[3].Key})
+
+	assert.Equal(t, 0, groups[0].Geocoded)
+	assert.Equal(t, 2, groups[3].Geocoded)
+	assert.InDelta(t, 51.55, groups[3].Centroid.Latitude, 1e-3)
+	assert.InDelta(t, -0.1, groups[3].Centroid.Longitude, 1e-9)
+	assert.InDelta(t, 51.52, groups[1].Centroid.Latitude, 1e-9)
+}
+
+func TestCode(t *testing.T) {
+	level, err := aggregate.Code("lsoa")
+	assert.NoError(t, err)
+	assert.Equal(t, "lsoa_code", level.Name)
+
+	groups := aggregate.By(level, testPostcodes).Groups()
+	assert.Len(t, groups, 4)
+	assert.Equal(t, "", groups[0].Key)
+	assert.Equal(t, "E0Westminster 008B", groups[1].Key)
+
+	_, err = aggregate.Code("lsoa_name")
+	assert.ErrorIs(t, err, aggregate.ErrUnknownCode)
+
+	_, err = aggregate.Code("-")
+	assert.ErrorIs(t, err, aggregate.ErrUnknownCode)
+}
+
+func TestAddStream(t *testing.T) {
+	errLookup := errors.New("lookup failed")
+	in := make(chan postcodesio.LookupResult, 4)
+	in <- postcodesio.LookupResult{Query: "SW1A2AA", Result: &testPostcodes[0]}
+	in <- postcodesio.LookupResult{Query: "XX1 1XX"}
+	in <- postcodesio.LookupResult{Query: "NW16XE", Err: errLookup}
+	in <- postcodesio.LookupResult{Query: "SW1A1AA", Result: &testPostcodes[2]}
+	close(in)
+
+	a := aggregate.New(aggregate.District)
+	notFound, err := a.AddStream(in)
+	assert.ErrorIs(t, err, errLookup)
+	assert.Equal(t, 1, notFound)
+	assert.Equal(t, []aggregate.Group{{Key: "SW1A", Count: 2, Geocoded: 2, Centroid: a.Groups()[0].Centroid}}, a.Groups())
+}
+
+func TestWriteCSVAndJSON(t *testing.T) {
+	a := aggregate.By(aggregate.District, []postcodesio.Postcode{testPostcodes[3], testPostcodes[4]})
+
+	var buf bytes.Buffer
+	assert.NoError(t, a.WriteCSV(&buf))
+	assert.Equal(t, "district,count,geocoded,latitude,longitude\nGY1,1,0,,\nNW1,1,1,51.52,-0.16\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, a.WriteJSON(&buf))
+	assert.JSONEq(t, `{"level":"district","groups":[
+		{"key":"GY1","count":1,"geocoded":0},
+		{"key":"NW1","count":1,"geocoded":1,"latitude":51.52,"longitude":-0.16}
+	]}`, buf.String())
+}
@@ -0,0 +1,84 @@
+package aggregate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/leandrorondon/postcodesio-go"
+)
+
+// ErrUnknownCode is returned by Code for a name that is not a field of postcodesio.Codes.
+var ErrUnknownCode = errors.New("aggregate: unknown code")
+
+// Level is a level of the hierarchy postcodes are grouped by, e.g. the postcode district or the LSOA.
+type Level struct {
+	// Name identifies the level in the CSV and JSON outputs.
+	Name string
+	// Key returns the group of a postcode, or "" if the postcode has no value for the level.
+	Key func(p postcodesio.Postcode) string
+}
+
+// Levels of the postcode hierarchy, derived from the postcode itself.
+var (
+	// Area is the postcode area, e.g. "SW".
+	Area = Level{Name: "area", Key: func(p postcodesio.Postcode) string { return parse(p).Area() }}
+	// District is the postcode district, e.g. "SW1A".
+	District = Level{Name: "district", Key: func(p postcodesio.Postcode) string { return parse(p).District() }}
+	// Sector is the postcode sector, e.g. "SW1A 2".
+	Sector = Level{Name: "sector", Key: sector}
+	// Outcode is the outward code returned by the API, which is the postcode district.
+	Outcode = Level{Name: "outcode", Key: func(p postcodesio.Postcode) string { return p.Outcode }}
+)
+
+// Levels of the administrative and statistical geographies, by name as returned by the API.
+var (
+	LSOA          = Level{Name: "lsoa", Key: func(p postcodesio.Postcode) string { return p.LSOA.Value }}
+	MSOA          = Level{Name: "msoa", Key: func(p postcodesio.Postcode) string { return p.MSOA.Value }}
+	Ward          = Level{Name: "admin_ward", Key: func(p postcodesio.Postcode) string { return p.AdminWard.Value }}
+	AdminDistrict = Level{Name: "admin_district", Key: func(p postcodesio.Postcode) string { return p.AdminDistrict.Value }}
+	AdminCounty   = Level{Name: "admin_county", Key: func(p postcodesio.Postcode) string { return p.AdminCounty.Value }}
+	Region        = Level{Name: "region", Key: func(p postcodesio.Postcode) string { return p.Region.Value }}
+	Country       = Level{Name: "country", Key: func(p postcodesio.Postcode) string { return p.Country }}
+)
+
+// Code returns the level grouping postcodes by a field of postcodesio.Codes, named as in the API, e.g. "lsoa" or
+// "admin_district". Codes identify areas unambiguously, unlike names.
+func Code(name string) (Level, error) {
+	t := reflect.TypeOf(postcodesio.Codes{})
+
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag != name || tag == "-" || t.Field(i).Type.Kind() != reflect.String {
+			continue
+		}
+
+		index := i
+
+		return Level{
+			Name: name + "_code",
+			Key: func(p postcodesio.Postcode) string {
+				return reflect.ValueOf(p.Codes).Field(index).String()
+			},
+		}, nil
+	}
+
+	return Level{}, fmt.Errorf("%w: %q", ErrUnknownCode, name)
+}
+
+// parse returns the parts of the postcode, which are empty if it is not well-formed.
+func parse(p postcodesio.Postcode) postcodesio.PostcodeParts {
+	parts, _ := postcodesio.ParsePostcode(p.Postcode)
+
+	return parts
+}
+
+func sector(p postcodesio.Postcode) string {
+	parts := parse(p)
+	if parts.Incode == "" {
+		return ""
+	}
+
+	return parts.Sector()
+}
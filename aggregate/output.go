@@ -0,0 +1,67 @@
+package aggregate
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// jsonOutput is the JSON output of an Aggregator.
+type jsonOutput struct {
+	Level  string      `json:"level"`
+	Groups []jsonGroup `json:"groups"`
+}
+
+type jsonGroup struct {
+	Key       string   `json:"key"`
+	Count     int      `json:"count"`
+	Geocoded  int      `json:"geocoded"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// WriteCSV writes the groups as CSV, with a header row naming the level, e.g.
+// "district,count,geocoded,latitude,longitude". The centroid columns are empty for groups without coordinates.
+func (a *Aggregator) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{a.level.Name, "count", "geocoded", "latitude", "longitude"}); err != nil {
+		return err
+	}
+
+	for _, g := range a.Groups() {
+		record := []string{g.Key, strconv.Itoa(g.Count), strconv.Itoa(g.Geocoded), "", ""}
+
+		if g.Geocoded > 0 {
+			record[3] = strconv.FormatFloat(g.Centroid.Latitude, 'f', -1, 64)
+			record[4] = strconv.FormatFloat(g.Centroid.Longitude, 'f', -1, 64)
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WriteJSON writes the groups as a JSON object with the level name and the groups, whose latitude and longitude are
+// omitted if they have no coordinates.
+func (a *Aggregator) WriteJSON(w io.Writer) error {
+	groups := a.Groups()
+	out := jsonOutput{Level: a.level.Name, Groups: make([]jsonGroup, len(groups))}
+
+	for i, g := range groups {
+		out.Groups[i] = jsonGroup{Key: g.Key, Count: g.Count, Geocoded: g.Geocoded}
+
+		if g.Geocoded > 0 {
+			out.Groups[i].Latitude = &groups[i].Centroid.Latitude
+			out.Groups[i].Longitude = &groups[i].Centroid.Longitude
+		}
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
@@ -13,7 +13,7 @@ type PostcodeLookupResponse struct {
 // Filters parameter is optional.
 type BulkPostCodeLookupRequest struct {
 	Postcodes []string `json:"postcodes"`
-	Filters   []string `json:"-"`
+	Filters   []Field  `json:"-"`
 }
 
 // BulkPostcodeLookupResponse represents the response of the Bulk Postcode Lookup API method.
@@ -30,13 +30,14 @@ type BulkPostcodeLookupQueryResponse struct {
 
 // ReverseGeocodingRequest is the input for the Reverse Geocoding API method.
 // Longitude and Latitude parameters are required.
-// Limit, Radius and WideSearch parameters are optional.
+// Limit, Radius, WideSearch and Filters parameters are optional.
 type ReverseGeocodingRequest struct {
 	Latitude   float64
 	Longitude  float64
 	Limit      int
 	Radius     float64
 	WideSearch bool
+	Filters    []Field
 }
 
 // ReverseGeocodingResponse represents the response of the Reverse Geocoding API method.
@@ -44,3 +45,34 @@ type ReverseGeocodingResponse struct {
 	Status int               `json:"status"`
 	Result []ReversePostcode `json:"result"`
 }
+
+// BulkReverseGeocodingRequest is the input for the Bulk Reverse Geocoding API method.
+// Geolocations parameter is required.
+// Filters parameter is optional.
+type BulkReverseGeocodingRequest struct {
+	Geolocations []Geolocation `json:"geolocations"`
+	Filters      []Field       `json:"-"`
+}
+
+// Geolocation is a query of the Bulk Reverse Geocoding API method.
+// Longitude and Latitude parameters are required.
+// Limit, Radius and WideSearch parameters are optional.
+type Geolocation struct {
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Limit      int     `json:"limit,omitempty"`
+	Radius     float64 `json:"radius,omitempty"`
+	WideSearch bool    `json:"widesearch,omitempty"` //nolint: tagliatelle
+}
+
+// BulkReverseGeocodingResponse represents the response of the Bulk Reverse Geocoding API method.
+type BulkReverseGeocodingResponse struct {
+	Status int                                 `json:"status"`
+	Result []BulkReverseGeocodingQueryResponse `json:"result"`
+}
+
+// BulkReverseGeocodingQueryResponse is the result of a query from Bulk Reverse Geocoding response.
+type BulkReverseGeocodingQueryResponse struct {
+	Query  Geolocation       `json:"query"`
+	Result []ReversePostcode `json:"result"`
+}
@@ -0,0 +1,77 @@
+package postcodesio
+
+import (
+	"context"
+	"strings"
+
+	"github.com/leandrorondon/postcodesio-go/geo"
+)
+
+// Geohash returns the geohash of the postcode with the given precision, see geo.Geohash, and whether it is geocoded.
+func (p Postcode) Geohash(precision int) (string, bool) {
+	lat, lon, ok := p.Location()
+	if !ok {
+		return "", false
+	}
+
+	return geo.Geohash(geo.Point{Latitude: lat, Longitude: lon}, precision), true
+}
+
+// HexCell returns the cell of the grid containing the postcode, and whether it is geocoded.
+func (p Postcode) HexCell(grid geo.HexGrid) (geo.HexCell, bool) {
+	lat, lon, ok := p.Location()
+	if !ok {
+		return geo.HexCell{}, false
+	}
+
+	return grid.Cell(geo.Point{Latitude: lat, Longitude: lon}), true
+}
+
+// GeohashPostcodes returns the postcodes inside the geohash cell, sorted by postcode. Cells of precision 6 or more,
+// about 1.2 km by 0.6 km, take a single reverse geocoding query of the cell centre; larger cells are tiled as in
+// SearchPolygon, which also describes ErrSearchTruncated and filters.
+func (c *Client) GeohashPostcodes(ctx context.Context, hash string, opts ...RequestOption) ([]Postcode, error) {
+	box, err := geo.DecodeGeohash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	hash = strings.ToLower(hash)
+	found, err := c.search(ctx, cellArea{box: box, in: func(p geo.Point) bool {
+		return geo.Geohash(p, len(hash)) == hash
+	}}, opts)
+
+	return sortedPostcodes(found), err
+}
+
+// HexCellPostcodes returns the postcodes inside the cell of the grid, sorted by postcode. Cells of grids with a size
+// up to 1.5 km take a single reverse geocoding query of the cell centre; larger cells are tiled as in
+// SearchPolygon, which also describes ErrSearchTruncated and filters.
+func (c *Client) HexCellPostcodes(
+	ctx context.Context, grid geo.HexGrid, cell geo.HexCell, opts ...RequestOption,
+) ([]Postcode, error) {
+	found, err := c.search(ctx, cellArea{box: grid.Polygon(cell).BoundingBox(), in: func(p geo.Point) bool {
+		return grid.Cell(p) == cell
+	}}, opts)
+
+	return sortedPostcodes(found), err
+}
+
+// cellArea is a grid cell, given by its bounding box and its exact membership test. Points on the edges belong to
+// a single cell, as decided by the grid.
+type cellArea struct {
+	box geo.BoundingBox
+	in  func(p geo.Point) bool
+}
+
+func (a cellArea) bounds() geo.BoundingBox {
+	return a.box
+}
+
+func (a cellArea) intersects(b geo.BoundingBox) bool {
+	return a.box.Overlaps(b)
+}
+
+func (a cellArea) contains(p geo.Point) bool {
+	return a.in(p)
+}
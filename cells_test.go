@@ -0,0 +1,101 @@
+package postcodesio_test
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostcode_Cells(t *testing.T) {
+	p := postcodesio.ReversePostcode{Postcode: postcodesio.Postcode{
+		Latitude:  postcodesio.Some(51.523659),
+		Longitude: postcodesio.Some(-0.158541),
+	}}
+	grid := geo.HexGrid{Size: 1000}
+
+	hash, ok := p.Geohash(7)
+	assert.True(t, ok)
+	assert.Equal(t, "gcpvh7s", hash)
+
+	cell, ok := p.HexCell(grid)
+	assert.True(t, ok)
+	assert.Equal(t, grid.Cell(geo.Point{Latitude: 51.523659, Longitude: -0.158541}), cell)
+
+	_, ok = postcodesio.Postcode{}.Geohash(7)
+	assert.False(t, ok)
+
+	_, ok = postcodesio.Postcode{}.HexCell(grid)
+	assert.False(t, ok)
+}
+
+func TestGeohashPostcodes(t *testing.T) {
+	var queries atomic.Int32
+
+	dataset := randomDataset(3000)
+	srv := newReverseGeocodingServer(t, dataset, &queries)
+	defer srv.Close()
+
+	hash := "gcpvh7"
+
+	var expected []string
+
+	for _, p := range dataset {
+		if geo.Geohash(p.point, len(hash)) == hash {
+			expected = append(expected, p.postcode)
+		}
+	}
+
+	sort.Strings(expected)
+
+	c := postcodesio.NewTestClient(srv.URL)
+	results, err := c.GeohashPostcodes(context.Background(), "GCPVH7")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, postcodeNames(results))
+	assert.NotEmpty(t, expected)
+	assert.Equal(t, int32(1), queries.Load())
+
+	_, err = c.GeohashPostcodes(context.Background(), "gcpa")
+	assert.ErrorIs(t, err, geo.ErrInvalidGeohash)
+}
+
+func TestHexCellPostcodes(t *testing.T) {
+	var queries atomic.Int32
+
+	dataset := randomDataset(3000)
+	srv := newReverseGeocodingServer(t, dataset, &queries)
+	defer srv.Close()
+
+	grid := geo.HexGrid{Size: 500}
+	cell := grid.Cell(geo.Point{Latitude: 51.523659, Longitude: -0.158541})
+
+	var expected []string
+
+	for _, p := range dataset {
+		if grid.Cell(p.point) == cell {
+			expected = append(expected, p.postcode)
+		}
+	}
+
+	sort.Strings(expected)
+
+	c := postcodesio.NewTestClient(srv.URL)
+	results, err := c.HexCellPostcodes(context.Background(), grid, cell)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, postcodeNames(results))
+	assert.NotEmpty(t, expected)
+	assert.Equal(t, int32(1), queries.Load())
+}
+
+func postcodeNames(postcodes []postcodesio.Postcode) []string {
+	names := make([]string, len(postcodes))
+	for i, p := range postcodes {
+		names[i] = p.Postcode
+	}
+
+	return names
+}
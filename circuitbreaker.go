@@ -0,0 +1,200 @@
+package postcodesio
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultCoolDown         = 30 * time.Second
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request fast with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through to decide whether to close the circuit.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig is the configuration of the circuit breaker set with WithCircuitBreaker.
+// FailureThreshold is the number of consecutive failed requests that opens the circuit, defaults to 5.
+// CoolDown is for how long the circuit stays open before letting probes through, defaults to 30 seconds.
+// HalfOpenMaxRequests is the number of concurrent probes allowed while half-open, defaults to 1.
+// OnStateChange is optional, and is called on every state transition.
+type CircuitBreakerConfig struct {
+	FailureThreshold    int
+	CoolDown            time.Duration
+	HalfOpenMaxRequests int
+	OnStateChange       func(from, to CircuitState)
+}
+
+// WithCircuitBreaker is the option to protect the Client with a circuit breaker. Connection errors, timeouts and 5xx
+// responses, after failing over all endpoints, count as failures. Requests cancelled by the caller do not. While the
+// circuit is open, requests fail with ErrCircuitOpen without reaching the API.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) ClientOption {
+	return func(c *Client) {
+		c.breaker = newCircuitBreaker(cfg)
+	}
+}
+
+// CircuitState returns the state of the Client circuit breaker, CircuitClosed if it has none. An open circuit whose
+// cool down has elapsed is reported half-open, as the next request is let through as a probe.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+
+	return c.breaker.current()
+}
+
+// circuitBreaker implements the circuit breaker pattern around the Client request path.
+// generation is incremented each time the circuit opens, so that the outcome of a request allowed before is ignored:
+// a slow success from before the outage must not close the circuit.
+type circuitBreaker struct {
+	mu         sync.Mutex
+	cfg        CircuitBreakerConfig
+	state      CircuitState
+	failures   int
+	openedAt   time.Time
+	probes     int
+	generation uint64
+	now        func() time.Time
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultFailureThreshold
+	}
+
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = defaultCoolDown
+	}
+
+	if cfg.HalfOpenMaxRequests <= 0 {
+		cfg.HalfOpenMaxRequests = 1
+	}
+
+	return &circuitBreaker{cfg: cfg, now: time.Now}
+}
+
+// allow returns ErrCircuitOpen if the request must not be sent. Every allowed request must be followed by a call to
+// record or release with the returned generation.
+func (b *circuitBreaker) allow() (uint64, error) {
+	b.mu.Lock()
+
+	from := b.state
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cfg.CoolDown {
+		b.state = CircuitHalfOpen
+		b.probes = 0
+	}
+
+	var err error
+
+	switch b.state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.probes >= b.cfg.HalfOpenMaxRequests {
+			err = ErrCircuitOpen
+		} else {
+			b.probes++
+		}
+	case CircuitClosed:
+	}
+
+	to, generation := b.state, b.generation
+	b.mu.Unlock()
+
+	b.notify(from, to)
+
+	return generation, err
+}
+
+// record records the outcome of a request allowed in the given generation.
+func (b *circuitBreaker) record(generation uint64, failed bool) {
+	b.mu.Lock()
+
+	if generation != b.generation {
+		b.mu.Unlock()
+
+		return
+	}
+
+	from := b.state
+
+	switch {
+	case !failed:
+		b.failures = 0
+		b.state = CircuitClosed
+	case b.state == CircuitHalfOpen:
+		b.open()
+	default:
+		b.failures++
+		if b.state == CircuitClosed && b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	}
+
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// release ends an allowed request whose outcome says nothing about the API health, e.g. cancelled by the caller.
+func (b *circuitBreaker) release(generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation == b.generation && b.state == CircuitHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// current returns the state of the circuit, without transitioning it.
+func (b *circuitBreaker) current() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cfg.CoolDown {
+		return CircuitHalfOpen
+	}
+
+	return b.state
+}
+
+// open opens the circuit. It must be called with the lock held.
+func (b *circuitBreaker) open() {
+	b.state = CircuitOpen
+	b.openedAt = b.now()
+	b.failures = 0
+	b.generation++
+}
+
+func (b *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,253 @@
+package postcodesio_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		calls       int
+		healthy     bool
+		transitions []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, `{"status":503}`)
+
+			return
+		}
+
+		fmt.Fprintf(w, `{"status":200}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithCircuitBreaker(postcodesio.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		CoolDown:         20 * time.Millisecond,
+		OnStateChange: func(from, to postcodesio.CircuitState) {
+			transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+		},
+	}))
+
+	for i := 0; i < 2; i++ {
+		_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+		assert.NoError(t, err)
+	}
+
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.ErrorIs(t, err, postcodesio.ErrCircuitOpen)
+	assert.Equal(t, 2, calls, "requests must not be sent while the circuit is open")
+	assert.Equal(t, postcodesio.CircuitOpen, c.CircuitState())
+
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, postcodesio.CircuitHalfOpen, c.CircuitState())
+
+	res, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.Status)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, postcodesio.CircuitClosed, c.CircuitState())
+	assert.Equal(t, postcodesio.CircuitClosed, postcodesio.New().CircuitState())
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, transitions)
+}
+
+func TestWithCircuitBreaker_FailedProbe(t *testing.T) {
+	var calls int
+
+	var fn roundTripFunc = func(req *http.Request) (*http.Response, error) {
+		calls++
+
+		return nil, fmt.Errorf("connection refused")
+	}
+
+	var transitions []string
+
+	c := postcodesio.NewTestClient("http://localhost", postcodesio.WithTransport(fn),
+		postcodesio.WithCircuitBreaker(postcodesio.CircuitBreakerConfig{
+			FailureThreshold: 1,
+			CoolDown:         10 * time.Millisecond,
+			OnStateChange: func(from, to postcodesio.CircuitState) {
+				transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+			},
+		}))
+
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.ErrorContains(t, err, "connection refused")
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.ErrorContains(t, err, "connection refused")
+
+	_, err = c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.ErrorIs(t, err, postcodesio.ErrCircuitOpen)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open"}, transitions)
+}
+
+func TestWithCircuitBreaker_ClientErrorsAreNotFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"status":404,"error":"Postcode not found"}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithCircuitBreaker(postcodesio.CircuitBreakerConfig{FailureThreshold: 1}))
+
+	for i := 0; i < 3; i++ {
+		res, err := c.PostcodeLookup(context.Background(), "XX11XX")
+		assert.NoError(t, err)
+		assert.Equal(t, 404, res.Status)
+	}
+}
+
+func TestWithCircuitBreaker_Timeouts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+
+		fmt.Fprintf(w, `{"status":200}`)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name  string
+		call  func(c *postcodesio.Client) error
+		opens bool
+	}{
+		{
+			name: "caller deadline",
+			call: func(c *postcodesio.Client) error {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				_, err := c.PostcodeLookup(ctx, "NW16XE")
+
+				return err
+			},
+			opens: true,
+		},
+		{
+			name: "request timeout",
+			call: func(c *postcodesio.Client) error {
+				_, err := c.PostcodeLookup(context.Background(), "NW16XE", postcodesio.WithRequestTimeout(10*time.Millisecond))
+
+				return err
+			},
+			opens: true,
+		},
+		{
+			name: "cancelled by the caller",
+			call: func(c *postcodesio.Client) error {
+				ctx, cancel := context.WithCancel(context.Background())
+				timer := time.AfterFunc(10*time.Millisecond, cancel)
+				defer timer.Stop()
+
+				_, err := c.PostcodeLookup(ctx, "NW16XE")
+
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := postcodesio.NewTestClient(srv.URL, postcodesio.WithCircuitBreaker(postcodesio.CircuitBreakerConfig{
+				FailureThreshold: 2,
+				CoolDown:         time.Minute,
+			}))
+
+			for i := 0; i < 2; i++ {
+				err := test.call(c)
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, postcodesio.ErrCircuitOpen)
+			}
+
+			err := test.call(c)
+			if test.opens {
+				assert.ErrorIs(t, err, postcodesio.ErrCircuitOpen)
+			} else {
+				assert.NotErrorIs(t, err, postcodesio.ErrCircuitOpen)
+			}
+		})
+	}
+}
+
+func TestWithCircuitBreaker_StaleSuccess(t *testing.T) {
+	arrived, release := make(chan struct{}), make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/postcodes/SLOW" {
+			close(arrived)
+			<-release
+			fmt.Fprintf(w, `{"status":200}`)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, `{"status":503}`)
+	}))
+	defer srv.Close()
+
+	var (
+		mu          sync.Mutex
+		transitions []string
+	)
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithCircuitBreaker(postcodesio.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		CoolDown:         time.Minute,
+		OnStateChange: func(from, to postcodesio.CircuitState) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+		},
+	}))
+
+	done := make(chan error)
+
+	go func() {
+		_, err := c.PostcodeLookup(context.Background(), "SLOW")
+		done <- err
+	}()
+
+	<-arrived
+
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+
+	close(release)
+	assert.NoError(t, <-done)
+
+	_, err = c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.ErrorIs(t, err, postcodesio.ErrCircuitOpen, "a success allowed before the circuit opened must not close it")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, []string{"closed->open"}, transitions)
+}
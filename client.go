@@ -4,20 +4,33 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
 )
 
 const (
-	baseURL        = "https://api.postcodes.io"
-	defaultTimeout = 30 * time.Second
+	baseURL                = "https://api.postcodes.io"
+	defaultTimeout         = 30 * time.Second
+	defaultMaxResponseSize = 64 << 20
+	defaultUserAgent       = "postcodesio-go (+https://github.com/leandrorondon/postcodesio-go)"
 )
 
 // Client is the base struct for the postcode.io API client.
 type Client struct {
-	baseURL    string
+	endpoints  *endpointPool
+	breaker    *circuitBreaker
+	hedger     *hedger
 	httpClient *http.Client
+	logger     *slog.Logger
+	logKey     []byte
+	header     http.Header
+	apiKey     string
+
+	maxResponseSize int64
 }
 
 // ClientOption describes the type for functional options used when creating a Client.
@@ -37,13 +50,82 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithLogger is the option to set a structured logger to the Client.
+// Requests are logged at debug level and failures at warn level. Postcodes and coordinates are replaced by an HMAC
+// with a random key of the Client before being logged, as they may be personal data: the same value can be
+// correlated across the entries of a Client, but not recovered.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+		c.logKey = newLogKey()
+	}
+}
+
+// WithBaseURL is the option to set the URL of the API, e.g. of a self-hosted postcodes.io instance.
+func WithBaseURL(url string) ClientOption {
+	return WithEndpoints(url)
+}
+
+// WithEndpoints is the option to set the URLs of the API the Client fails over between.
+// Requests are sent to the primary endpoint and, on connection errors or 5xx responses, to the fallbacks in order.
+// An endpoint that fails is skipped for a cooldown period, after which it is probed again.
+func WithEndpoints(primary string, fallbacks ...string) ClientOption {
+	return func(c *Client) {
+		c.endpoints.set(append([]string{primary}, fallbacks...)...)
+	}
+}
+
+// WithEndpointCooldown is the option to set for how long a failed endpoint is skipped.
+func WithEndpointCooldown(cooldown time.Duration) ClientOption {
+	return func(c *Client) {
+		c.endpoints.cooldown = cooldown
+	}
+}
+
+// WithUserAgent is the option to set the User-Agent header of the Client's requests.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.header.Set("User-Agent", userAgent)
+	}
+}
+
+// WithHeaders is the option to set headers on every request of the Client. They replace the Client's headers of the
+// same name, e.g. the default User-Agent. Headers set on a call with WithHeader replace the Client's headers of the
+// same name in turn.
+func WithHeaders(header http.Header) ClientOption {
+	return func(c *Client) {
+		for key, values := range header {
+			c.header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// WithAPIKey is the option to send an API key in the given header of every request, e.g. for postcodes.io compatible
+// providers or gateways requiring authentication. The key is redacted from logs and errors.
+func WithAPIKey(header, key string) ClientOption {
+	return func(c *Client) {
+		c.header.Set(header, key)
+		c.apiKey = key
+	}
+}
+
+// WithMaxResponseSize is the option to set the maximum size in bytes of a response body, defaults to 64 MiB.
+// Reading a larger response fails with ErrResponseTooLarge. A size of 0 removes the limit.
+func WithMaxResponseSize(size int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseSize = size
+	}
+}
+
 // New creates a new Client.
 func New(opts ...ClientOption) *Client {
 	c := &Client{
-		baseURL: baseURL,
+		endpoints: newEndpointPool(baseURL),
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
+		header:          http.Header{"User-Agent": {defaultUserAgent}},
+		maxResponseSize: defaultMaxResponseSize,
 	}
 
 	for _, opt := range opts {
@@ -53,46 +135,290 @@ func New(opts ...ClientOption) *Client {
 	return c
 }
 
-// get executes a http get request.
-func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+// Raw sends a request to the API through the Client request path, with failover, circuit breaker and hedging, and
+// returns the response body and status as received. path is relative to the API URL and may include a query, e.g.
+// "/postcodes?lon=-0.158541&lat=51.523659". payload, if not nil, is sent as a JSON body.
+func (c *Client) Raw(ctx context.Context, method, path string, payload []byte, opts ...RequestOption) ([]byte, int, error) {
+	res, err := c.do(ctx, method, path, payload, newRequestOptions(opts))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return c.doRequest(req)
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, res.StatusCode, err
+	}
+
+	return b, res.StatusCode, nil
+}
+
+// get executes a http get request and decodes the response body into v.
+func (c *Client) get(ctx context.Context, path string, opts *requestOptions, v any) error {
+	res, err := c.do(ctx, http.MethodGet, path, nil, opts)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// post executes a http post request and decodes the response body into v.
+func (c *Client) post(ctx context.Context, path string, body interface{}, opts *requestOptions, v any) error {
+	res, err := c.postResponse(ctx, path, body, opts)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// postEach executes a http post request and calls fn with each item of the result of the response as it is decoded.
+// It fails with ErrUnexpectedStatus if the response status is not 200.
+func postEach[T any](ctx context.Context, c *Client, path string, body interface{}, opts *requestOptions, fn func(T) error) error {
+	res, err := c.postResponse(ctx, path, body, opts)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	// Error responses, e.g. from a proxy, may not be JSON.
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.StatusCode)
+	}
+
+	status, err := decodeResults(res.Body, fn)
+	if err != nil {
+		return err
+	}
+
+	if status != 0 && status != http.StatusOK {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, status)
+	}
+
+	return nil
 }
 
-// post executes a http post request.
-func (c *Client) post(ctx context.Context, url string, body interface{}) ([]byte, error) {
+// postResponse executes a http post request and returns the response, whose body must be closed by the caller.
+func (c *Client) postResponse(ctx context.Context, path string, body interface{}, opts *requestOptions) (*http.Response, error) {
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(payload))
+	return c.do(ctx, http.MethodPost, path, payload, opts)
+}
+
+// do sends the request with the per-call timeout, if set, which lasts until the response body is closed.
+// The response body is limited to the maximum response size, and must be closed by the caller.
+func (c *Client) do(ctx context.Context, method, path string, payload []byte, opts *requestOptions) (*http.Response, error) {
+	var cancel context.CancelFunc = func() {}
+
+	if opts.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+	}
+
+	res, err := c.breakerSend(ctx, method, path, payload, opts)
 	if err != nil {
+		cancel()
+
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
 
-	return c.doRequest(req)
+	return res, nil
 }
 
-// doRequest encapsulates an http request-response.
-func (c *Client) doRequest(req *http.Request) ([]byte, error) {
-	res, err := c.httpClient.Do(req)
+// breakerSend sends the request through the circuit breaker, if set.
+func (c *Client) breakerSend(ctx context.Context, method, path string, payload []byte, opts *requestOptions) (*http.Response, error) {
+	if c.breaker == nil {
+		return c.send(ctx, method, path, payload, opts)
+	}
+
+	generation, err := c.breaker.allow()
 	if err != nil {
 		return nil, err
 	}
 
-	defer res.Body.Close()
+	res, err := c.send(ctx, method, path, payload, opts)
+
+	switch {
+	// A request cancelled by the caller says nothing about the API health, unlike one that timed out.
+	case err != nil && errors.Is(ctx.Err(), context.Canceled):
+		c.breaker.release(generation)
+	// A response too large was still sent by a healthy API.
+	case errors.Is(err, ErrResponseTooLarge):
+		c.breaker.record(generation, false)
+	default:
+		c.breaker.record(generation, err != nil || res.StatusCode >= http.StatusInternalServerError)
+	}
+
+	return res, err
+}
+
+// send sends the request, hedging it if it is a GET request and hedging is set.
+func (c *Client) send(ctx context.Context, method, path string, payload []byte, opts *requestOptions) (*http.Response, error) {
+	if c.hedger != nil && method == http.MethodGet && !opts.noHedging {
+		return c.hedge(ctx, path, opts)
+	}
+
+	return c.failover(ctx, method, path, payload, opts)
+}
+
+// failover sends the request to each endpoint in turn, until one responds without a server error.
+// The response of the last endpoint tried is returned if all of them fail.
+func (c *Client) failover(ctx context.Context, method, path string, payload []byte, opts *requestOptions) (*http.Response, error) {
+	var (
+		last *http.Response
+		err  error
+	)
+
+	for _, e := range c.endpoints.candidates() {
+		req, reqErr := c.newRequest(ctx, method, e.url+path, payload, opts.header)
+		if reqErr != nil {
+			closeBody(last)
+
+			return nil, reqErr
+		}
+
+		var res *http.Response
+
+		res, err = c.doRequest(req)
+
+		// A response too large came from a healthy endpoint, and another endpoint would send the same.
+		if err != nil && (ctx.Err() != nil || errors.Is(err, ErrResponseTooLarge)) {
+			closeBody(last)
+
+			return nil, err
+		}
+
+		if err == nil && res.StatusCode < http.StatusInternalServerError {
+			closeBody(last)
+			c.endpoints.markUp(e)
+
+			return res, nil
+		}
+
+		c.endpoints.markDown(e)
+
+		if err == nil {
+			closeBody(last)
+			last = res
+		}
+	}
+
+	if err != nil {
+		closeBody(last)
+
+		return nil, err
+	}
+
+	return last, nil
+}
+
+// newRequest creates a http request with the Client's headers and the given ones, which replace the Client's headers of
+// the same name, and with a JSON body if payload is not nil.
+func (c *Client) newRequest(ctx context.Context, method, url string, payload []byte, header http.Header) (*http.Request, error) {
+	body := io.Reader(http.NoBody)
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = c.header.Clone()
+
+	for key, values := range header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// doRequest encapsulates an http request-response. The response body is limited to the maximum response size.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	res, err := c.httpClient.Do(req)
+	if err == nil && c.maxResponseSize > 0 && res.ContentLength > c.maxResponseSize {
+		res.Body.Close()
+
+		err = fmt.Errorf("%w: %d bytes", ErrResponseTooLarge, res.ContentLength)
+	}
 
-	b, err := io.ReadAll(res.Body)
 	if err != nil {
+		err = c.redactAPIKey(err)
+		c.logRequest(req, 0, time.Since(start), err)
+
 		return nil, err
 	}
 
-	return b, nil
+	c.logRequest(req, res.StatusCode, time.Since(start), nil)
+
+	if c.maxResponseSize > 0 {
+		res.Body = &limitedBody{ReadCloser: res.Body, remaining: c.maxResponseSize}
+	}
+
+	return res, nil
+}
+
+// closeBody closes the body of res, if not nil.
+func closeBody(res *http.Response) {
+	if res != nil {
+		res.Body.Close()
+	}
+}
+
+// cancelBody is a response body that cancels the context of the request once closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer.
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
+
+// limitedBody is a response body failing with ErrResponseTooLarge once more than a number of bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+// Read implements io.Reader.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+
+	if b.remaining < 0 {
+		return n + int(b.remaining), ErrResponseTooLarge
+	}
+
+	return n, err
 }
@@ -1,10 +1,16 @@
 package postcodesio_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,35 +23,39 @@ var testPostcode = postcodesio.Postcode{
 	Outcode:                   "NW1",
 	Incode:                    "6XE",
 	Quality:                   1,
-	Eastings:                  527850,
-	Northings:                 182134,
+	Eastings:                  postcodesio.Some(527850),
+	Northings:                 postcodesio.Some(182134),
 	Country:                   "England",
-	NHSHA:                     "London",
-	AdminCounty:               "",
-	AdminDistrict:             "Westminster",
-	AdminWard:                 "Regent's Park",
-	Longitude:                 -0.158541,
-	Latitude:                  51.523659,
-	ParliamentaryConstituency: "Cities of London and Westminster",
-	PrimaryCareTrust:          "Westminster",
-	Region:                    "London",
-	Parish:                    "Westminster, unparished area",
-	LSOA:                      "Westminster 008B",
-	MSOA:                      "Westminster 008",
-	CED:                       "",
-	CCG:                       "NHS North West London",
-	NUTS:                      "Westminster",
+	NHSHA:                     postcodesio.Some("London"),
+	AdminCounty:               postcodesio.Optional[string]{},
+	AdminDistrict:             postcodesio.Some("Westminster"),
+	AdminWard:                 postcodesio.Some("Regent's Park"),
+	Longitude:                 postcodesio.Some(-0.158541),
+	Latitude:                  postcodesio.Some(51.523659),
+	ParliamentaryConstituency: postcodesio.Some("Cities of London and Westminster"),
+	EuropeanElectoralRegion:   postcodesio.Some("London"),
+	PrimaryCareTrust:          postcodesio.Some("Westminster"),
+	Region:                    postcodesio.Some("London"),
+	Parish:                    postcodesio.Some("Westminster, unparished area"),
+	LSOA:                      postcodesio.Some("Westminster 008B"),
+	MSOA:                      postcodesio.Some("Westminster 008"),
+	CED:                       postcodesio.Optional[string]{},
+	CCG:                       postcodesio.Some("NHS North West London"),
+	NUTS:                      postcodesio.Some("Westminster"),
 	Codes: postcodesio.Codes{
-		AdminCounty:   "E99999999",
-		AdminDistrict: "E09000033",
-		AdminWard:     "E05013805",
-		Parish:        "E43000236",
-		CCG:           "E38000256",
-		CCGCode:       "",
-		NUTS:          "TLI32",
-		LAU2:          "E09000033",
-		LSOA:          "E01004660",
-		MSOA:          "E02000967",
+		AdminCounty:               "E99999999",
+		AdminDistrict:             "E09000033",
+		AdminWard:                 "E05013805",
+		Parish:                    "E43000236",
+		ParliamentaryConstituency: "E14000639",
+		CCG:                       "E38000256",
+		CCGID:                     "W2U3Z",
+		CCGCode:                   "",
+		CED:                       "E99999999",
+		NUTS:                      "TLI32",
+		LAU2:                      "E09000033",
+		LSOA:                      "E01004660",
+		MSOA:                      "E02000967",
 	},
 }
 
@@ -63,7 +73,7 @@ func TestNew_WithTimeout(t *testing.T) {
 	defer srv.Close()
 
 	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithTimeout(time.Nanosecond))
-	res, err := c.PostcodeLookup(context.Background(), "")
+	res, err := c.PostcodeLookup(context.Background(), "NW16XE")
 
 	assert.Nil(t, res)
 	assert.ErrorContains(t, err, "context deadline exceeded (Client.Timeout exceeded while awaiting headers)")
@@ -85,8 +95,374 @@ func TestNew_WithTransport(t *testing.T) {
 	}
 
 	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithTransport(fn))
-	res, err := c.PostcodeLookup(context.Background(), "")
+	res, err := c.PostcodeLookup(context.Background(), "NW16XE")
 	assert.Nil(t, err)
 	assert.NotNil(t, res)
 	assert.True(t, called)
 }
+
+func TestNew_WithLogger(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		expectedLevel string
+	}{
+		{
+			name:          "successful request",
+			status:        http.StatusOK,
+			expectedLevel: `"level":"DEBUG"`,
+		},
+		{
+			name:          "error status",
+			status:        http.StatusNotFound,
+			expectedLevel: `"level":"WARN"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				fmt.Fprintf(w, `{"status":%d}`, test.status)
+			}))
+			defer srv.Close()
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			c := postcodesio.NewTestClient(srv.URL, postcodesio.WithLogger(logger))
+			_, err := c.PostcodeLookup(context.Background(), "NW1 6XE")
+			assert.NoError(t, err)
+
+			out := buf.String()
+			assert.Contains(t, out, test.expectedLevel)
+			assert.Contains(t, out, `"method":"GET"`)
+			assert.Contains(t, out, fmt.Sprintf(`"status":%d`, test.status))
+			assert.Contains(t, out, `"endpoint":"/postcodes/hmac:`)
+			assert.NotContains(t, out, "NW1")
+		})
+	}
+}
+
+func TestNew_WithLogger_KeyedHashes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":200}`)
+	}))
+	defer srv.Close()
+
+	endpoints := func(c *postcodesio.Client, buf *bytes.Buffer, postcodes ...string) []string {
+		var result []string
+
+		for _, postcode := range postcodes {
+			buf.Reset()
+
+			_, err := c.PostcodeLookup(context.Background(), postcode)
+			assert.NoError(t, err)
+
+			var entry struct {
+				Endpoint string `json:"endpoint"`
+			}
+
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			result = append(result, entry.Endpoint)
+		}
+
+		return result
+	}
+
+	var buf1, buf2 bytes.Buffer
+
+	debug := &slog.HandlerOptions{Level: slog.LevelDebug}
+	c1 := postcodesio.NewTestClient(srv.URL, postcodesio.WithLogger(slog.New(slog.NewJSONHandler(&buf1, debug))))
+	c2 := postcodesio.NewTestClient(srv.URL, postcodesio.WithLogger(slog.New(slog.NewJSONHandler(&buf2, debug))))
+
+	first := endpoints(c1, &buf1, "NW1 6XE", "nw16xe", "SW1A 2AA")
+	assert.Equal(t, first[0], first[1], "the same postcode must be correlated within a client")
+	assert.NotEqual(t, first[0], first[2])
+
+	second := endpoints(c2, &buf2, "NW1 6XE")
+	assert.NotEqual(t, first[0], second[0], "hashes must depend on the client key")
+}
+
+func TestNew_WithLogger_RedactsErrors(t *testing.T) {
+	var fn roundTripFunc = func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	c := postcodesio.NewTestClient("http://localhost", postcodesio.WithTransport(fn), postcodesio.WithLogger(logger))
+	_, err := c.ReverseGeocoding(context.Background(),
+		postcodesio.ReverseGeocodingRequest{Latitude: 51.523659, Longitude: -0.158541, Limit: 1})
+	assert.Error(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `"level":"WARN"`)
+	assert.Contains(t, out, "connection refused")
+	assert.Contains(t, out, "limit=1")
+	assert.NotContains(t, out, "51.523659")
+	assert.NotContains(t, out, "0.158541")
+}
+
+func TestNew_WithBaseURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/postcodes/NW16XE", r.URL.Path)
+		fmt.Fprintf(w, `{"status":200}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.New(postcodesio.WithBaseURL(srv.URL + "/v1/"))
+	res, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.Status)
+}
+
+func TestNew_WithEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		primary func(w http.ResponseWriter)
+	}{
+		{
+			name: "server error",
+			primary: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+		},
+		{
+			name: "connection error",
+			primary: func(w http.ResponseWriter) {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var primaryCalls, fallbackCalls atomic.Int32
+
+			primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				primaryCalls.Add(1)
+				test.primary(w)
+			}))
+			defer primary.Close()
+
+			fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fallbackCalls.Add(1)
+				fmt.Fprintf(w, `{"status":200}`)
+			}))
+			defer fallback.Close()
+
+			c := postcodesio.New(postcodesio.WithEndpoints(primary.URL, fallback.URL))
+
+			res, err := c.PostcodeLookup(context.Background(), "NW16XE")
+			assert.NoError(t, err)
+			assert.Equal(t, 200, res.Status)
+			assert.EqualValues(t, 1, primaryCalls.Load())
+			assert.EqualValues(t, 1, fallbackCalls.Load())
+
+			_, err = c.PostcodeLookup(context.Background(), "NW16XE")
+			assert.NoError(t, err)
+			assert.EqualValues(t, 1, primaryCalls.Load(), "primary must be skipped during cooldown")
+			assert.EqualValues(t, 2, fallbackCalls.Load())
+		})
+	}
+}
+
+func TestNew_WithEndpointCooldown(t *testing.T) {
+	var primaryCalls int
+
+	healthy := false
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		fmt.Fprintf(w, `{"status":200}`)
+	}))
+	defer primary.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status":200}`)
+	}))
+	defer fallback.Close()
+
+	c := postcodesio.New(
+		postcodesio.WithEndpoints(primary.URL, fallback.URL),
+		postcodesio.WithEndpointCooldown(10*time.Millisecond),
+	)
+
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, primaryCalls)
+
+	healthy = true
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, primaryCalls, "primary must be probed after cooldown")
+}
+
+func TestNew_WithEndpoints_AllDown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"status":500,"error":"Internal Server Error"}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.New(postcodesio.WithEndpoints(srv.URL, srv.URL))
+
+	res, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.Equal(t, 500, res.Status)
+}
+
+func TestNew_WithMaxResponseSize(t *testing.T) {
+	body := fmt.Sprintf(`{"status":200,"result":{"postcode":"NW1 6XE","admin_district":%q}}`, strings.Repeat("x", 1000))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("chunked") != "" {
+			w.(http.Flusher).Flush()
+		}
+
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		size     int64
+		path     string
+		tooLarge bool
+	}{
+		{"content length over limit", 500, "/postcodes/NW16XE", true},
+		{"chunked over limit", 500, "/postcodes/NW16XE?chunked=1", true},
+		{"exactly at limit", int64(len(body)), "/postcodes/NW16XE?chunked=1", false},
+		{"no limit", 0, "/postcodes/NW16XE?chunked=1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := postcodesio.NewTestClient(srv.URL, postcodesio.WithMaxResponseSize(tt.size))
+
+			b, status, err := c.Raw(context.Background(), http.MethodGet, tt.path, nil)
+			if tt.tooLarge {
+				assert.ErrorIs(t, err, postcodesio.ErrResponseTooLarge)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, body, string(b))
+		})
+	}
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithMaxResponseSize(500))
+
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE?chunked=1")
+	assert.ErrorIs(t, err, postcodesio.ErrResponseTooLarge)
+}
+
+func TestNew_WithMaxResponseSize_Healthy(t *testing.T) {
+	var primaryCalls, fallbackCalls atomic.Int32
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls.Add(1)
+		fmt.Fprintf(w, `{"status":200,"result":{"admin_district":%q}}`, strings.Repeat("x", 1000))
+	}))
+	defer primary.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackCalls.Add(1)
+		fmt.Fprintf(w, `{"status":200}`)
+	}))
+	defer fallback.Close()
+
+	c := postcodesio.New(
+		postcodesio.WithEndpoints(primary.URL, fallback.URL),
+		postcodesio.WithMaxResponseSize(500),
+		postcodesio.WithCircuitBreaker(postcodesio.CircuitBreakerConfig{FailureThreshold: 1}),
+	)
+
+	// An oversized response is no sign of an unhealthy endpoint: it is neither failed over nor a breaker failure.
+	for i := 0; i < 3; i++ {
+		_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+		assert.ErrorIs(t, err, postcodesio.ErrResponseTooLarge)
+	}
+
+	assert.EqualValues(t, 3, primaryCalls.Load())
+	assert.Zero(t, fallbackCalls.Load())
+}
+
+func TestNew_WithUserAgentAndHeaders(t *testing.T) {
+	var header http.Header
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		fmt.Fprintf(w, `{"status":200}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(header.Get("User-Agent"), "postcodesio-go"))
+
+	c = postcodesio.NewTestClient(srv.URL,
+		postcodesio.WithUserAgent("my-app/1.0"),
+		postcodesio.WithHeaders(http.Header{"X-Tenant": {"acme"}, "X-Trace": {"a"}}),
+	)
+	_, err = c.PostcodeLookup(context.Background(), "NW16XE", postcodesio.WithHeader("X-Trace", "b"))
+	assert.NoError(t, err)
+	assert.Equal(t, "my-app/1.0", header.Get("User-Agent"))
+	assert.Equal(t, "acme", header.Get("X-Tenant"))
+	assert.Equal(t, []string{"b"}, header.Values("X-Trace"))
+
+	_, err = c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, header.Values("X-Trace"), "call headers must not leak into the Client's headers")
+
+	c = postcodesio.NewTestClient(srv.URL, postcodesio.WithHeaders(http.Header{"user-agent": {"my-service/1.0"}}))
+	_, err = c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"my-service/1.0"}, header.Values("User-Agent"), "headers must replace the default User-Agent")
+}
+
+func TestNew_WithAPIKey(t *testing.T) {
+	const key = "s3cr3t-k3y"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, key, r.Header.Get("X-Api-Key"))
+		fmt.Fprintf(w, `{"status":200}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithAPIKey("X-Api-Key", key))
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+
+	errGateway := errors.New("gateway rejected key " + key)
+
+	var fn roundTripFunc = func(req *http.Request) (*http.Response, error) {
+		return nil, errGateway
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	c = postcodesio.NewTestClient("http://localhost", postcodesio.WithTransport(fn), postcodesio.WithLogger(logger),
+		postcodesio.WithAPIKey("X-Api-Key", key))
+	_, err = c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.ErrorIs(t, err, errGateway)
+	assert.NotContains(t, err.Error(), key)
+	assert.Contains(t, err.Error(), "[REDACTED]")
+	assert.Contains(t, buf.String(), "gateway rejected key")
+	assert.NotContains(t, buf.String(), key)
+}
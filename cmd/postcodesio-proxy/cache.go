@@ -0,0 +1,77 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cache is a LRU cache of upstream responses, whose entries expire after a TTL.
+type cache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	key     string
+	res     response
+	expires time.Time
+}
+
+// newCache creates a cache of up to size entries. A cache of size 0 stores nothing.
+func newCache(size int, ttl time.Duration) *cache {
+	return &cache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+func (c *cache) get(key string) (response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return response{}, false
+	}
+
+	entry := el.Value.(*cacheEntry) //nolint: forcetypeassert
+	if c.now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+
+		return response{}, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return entry.res, true
+}
+
+func (c *cache) set(key string, res response) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, res: res, expires: c.now().Add(c.ttl)})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key) //nolint: forcetypeassert
+	}
+}
@@ -0,0 +1,100 @@
+// Command postcodesio-proxy is a caching reverse proxy for postcodes.io.
+//
+// It exposes the postcodes.io REST paths, e.g. GET /postcodes/:postcode or POST /postcodes, and forwards them through
+// a postcodesio.Client, with endpoint failover, an optional circuit breaker, hedging and upstream rate limit. GET
+// responses are cached and concurrent identical GET requests are coalesced into a single upstream request. Response
+// bodies are returned as received from upstream. GET /healthz reports the circuit breaker state, failing with 503 while
+// the circuit is open, and GET /metrics exposes Prometheus metrics.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/leandrorondon/postcodesio-go"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	upstream := flag.String("upstream", "https://api.postcodes.io", "comma separated postcodes.io URLs, primary first")
+	timeout := flag.Duration("timeout", 10*time.Second, "upstream request timeout")
+	cacheSize := flag.Int("cache-size", 10000, "number of GET responses cached, 0 disables the cache")
+	cacheTTL := flag.Duration("cache-ttl", time.Hour, "for how long GET responses are cached")
+	breakerThreshold := flag.Int("breaker-threshold", 0, "consecutive upstream failures opening the circuit breaker, 0 disables it")
+	hedgeDelay := flag.Duration("hedge-delay", 0, "delay before hedging upstream GET requests, 0 disables hedging")
+	rateLimit := flag.Float64("rate-limit", 0, "upstream requests per second, 0 disables the limit")
+	rateBurst := flag.Int("rate-burst", 10, "upstream requests sent in a burst under the rate limit")
+	debug := flag.Bool("debug", false, "log every upstream request")
+	flag.Parse()
+
+	level := slog.LevelInfo
+	if *debug {
+		level = slog.LevelDebug
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	urls := strings.Split(*upstream, ",")
+	opts := []postcodesio.ClientOption{
+		postcodesio.WithEndpoints(urls[0], urls[1:]...),
+		postcodesio.WithTimeout(*timeout),
+		postcodesio.WithLogger(logger),
+	}
+
+	if *breakerThreshold > 0 {
+		opts = append(opts, postcodesio.WithCircuitBreaker(postcodesio.CircuitBreakerConfig{
+			FailureThreshold: *breakerThreshold,
+			OnStateChange: func(from, to postcodesio.CircuitState) {
+				logger.Warn("circuit breaker state changed", "from", from.String(), "to", to.String())
+			},
+		}))
+	}
+
+	if *hedgeDelay > 0 {
+		opts = append(opts, postcodesio.WithHedging(postcodesio.HedgePolicy{Delay: *hedgeDelay}))
+	}
+
+	if *rateLimit > 0 {
+		opts = append(opts, postcodesio.WithTransport(rateLimitedTransport{
+			next:    http.DefaultTransport,
+			limiter: newRateLimiter(*rateLimit, *rateBurst),
+		}))
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newProxy(postcodesio.New(opts...), *cacheSize, *cacheTTL),
+		ReadHeaderTimeout: *timeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("shutdown failed", "error", err)
+		}
+	}()
+
+	logger.Info("listening", "addr", *addr, "upstream", urls)
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+}
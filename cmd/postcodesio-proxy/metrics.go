@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// metrics are the proxy counters, exposed in the Prometheus text format.
+type metrics struct {
+	mu       sync.Mutex
+	requests map[string]uint64
+
+	cacheHits      atomic.Uint64
+	cacheMisses    atomic.Uint64
+	coalescedCalls atomic.Uint64
+	upstreamErrors atomic.Uint64
+}
+
+// knownMethods are the methods counted under their own label. Methods are client supplied, so others are counted as
+// "other" to bound the number of series.
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+func newMetrics() *metrics {
+	return &metrics{requests: make(map[string]uint64)}
+}
+
+func (m *metrics) request(method string, status int) {
+	if !knownMethods[method] {
+		method = "other"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[fmt.Sprintf(`method=%q,code="%d"`, method, status)]++
+}
+
+func (m *metrics) cacheHit()      { m.cacheHits.Add(1) }
+func (m *metrics) cacheMiss()     { m.cacheMisses.Add(1) }
+func (m *metrics) coalesced()     { m.coalescedCalls.Add(1) }
+func (m *metrics) upstreamError() { m.upstreamErrors.Add(1) }
+
+func (m *metrics) write(w io.Writer) {
+	m.mu.Lock()
+
+	labels := make([]string, 0, len(m.requests))
+	for l := range m.requests {
+		labels = append(labels, l)
+	}
+
+	sort.Strings(labels)
+
+	fmt.Fprintln(w, "# HELP postcodesio_proxy_requests_total Requests served, by method and status code.")
+	fmt.Fprintln(w, "# TYPE postcodesio_proxy_requests_total counter")
+
+	for _, l := range labels {
+		fmt.Fprintf(w, "postcodesio_proxy_requests_total{%s} %d\n", l, m.requests[l])
+	}
+
+	m.mu.Unlock()
+
+	writeCounter(w, "postcodesio_proxy_cache_hits_total", "GET requests served from the cache.", m.cacheHits.Load())
+	writeCounter(w, "postcodesio_proxy_cache_misses_total", "GET requests not found in the cache.", m.cacheMisses.Load())
+	writeCounter(w, "postcodesio_proxy_coalesced_requests_total", "GET requests sharing an upstream request.", m.coalescedCalls.Load())
+	writeCounter(w, "postcodesio_proxy_upstream_errors_total", "Upstream requests that failed.", m.upstreamErrors.Load())
+}
+
+func writeCounter(w io.Writer, name, help string, value uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %s\n", name, help, name, name, strconv.FormatUint(value, 10))
+}
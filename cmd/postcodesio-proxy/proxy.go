@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/leandrorondon/postcodesio-go"
+)
+
+// maxBodySize is the maximum size of a request body, well above the size of a 100 postcodes bulk request.
+const maxBodySize = 1 << 20
+
+// response is an upstream response.
+type response struct {
+	body   []byte
+	status int
+}
+
+// proxy is the http.Handler forwarding requests to postcodes.io.
+type proxy struct {
+	client  *postcodesio.Client
+	cache   *cache
+	flights flightGroup
+	metrics *metrics
+}
+
+func newProxy(client *postcodesio.Client, cacheSize int, cacheTTL time.Duration) *proxy {
+	return &proxy{
+		client:  client,
+		cache:   newCache(cacheSize, cacheTTL),
+		flights: flightGroup{calls: make(map[string]*flight)},
+		metrics: newMetrics(),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		res response
+		err error
+	)
+
+	switch {
+	case r.URL.Path == "/healthz":
+		p.health(w)
+
+		return
+	case r.URL.Path == "/metrics":
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		p.metrics.write(w)
+
+		return
+	case r.Method == http.MethodGet:
+		res, err = p.get(r)
+	case r.Method == http.MethodPost:
+		res, err = p.post(r)
+	default:
+		res = response{status: http.StatusMethodNotAllowed, body: errorBody(http.StatusMethodNotAllowed, "Method not allowed")}
+	}
+
+	p.serve(w, r, res, err)
+}
+
+// health reports the state of the upstream circuit breaker. It fails while the circuit is open, as requests to the
+// proxy fail too.
+func (p *proxy) health(w http.ResponseWriter) {
+	state := p.client.CircuitState()
+
+	status := http.StatusOK
+	if state == postcodesio.CircuitOpen {
+		status = http.StatusServiceUnavailable
+	}
+
+	type result struct {
+		Circuit string `json:"circuit"`
+	}
+
+	b, _ := json.Marshal(struct { //nolint: errchkjson
+		Status int    `json:"status"`
+		Result result `json:"result"`
+	}{status, result{state.String()}})
+
+	writeJSON(w, status, b)
+}
+
+// serve writes the upstream response, or an error if the upstream request failed.
+func (p *proxy) serve(w http.ResponseWriter, r *http.Request, res response, err error) {
+	switch {
+	case errors.Is(err, postcodesio.ErrCircuitOpen):
+		res.status = http.StatusServiceUnavailable
+		writeError(w, res.status, "Upstream unavailable")
+	case err != nil:
+		p.metrics.upstreamError()
+
+		res.status = http.StatusBadGateway
+		writeError(w, res.status, "Upstream request failed")
+	default:
+		writeJSON(w, res.status, res.body)
+	}
+
+	p.metrics.request(r.Method, res.status)
+}
+
+// get forwards a GET request, from the cache if possible, coalescing concurrent identical requests.
+func (p *proxy) get(r *http.Request) (response, error) {
+	key := r.URL.RequestURI()
+
+	if res, ok := p.cache.get(key); ok {
+		p.metrics.cacheHit()
+
+		return res, nil
+	}
+
+	p.metrics.cacheMiss()
+
+	// The upstream request is shared by every coalesced caller, so it must not be cancelled by the first one.
+	ctx := context.WithoutCancel(r.Context())
+
+	res, err, shared := p.flights.do(key, func() (response, error) {
+		body, status, err := p.client.Raw(ctx, http.MethodGet, key, nil)
+		if err != nil {
+			return response{}, err
+		}
+
+		res := response{body: body, status: status}
+		if status == http.StatusOK {
+			p.cache.set(key, res)
+		}
+
+		return res, nil
+	})
+
+	if shared {
+		p.metrics.coalesced()
+	}
+
+	return res, err
+}
+
+// post forwards a POST request.
+func (p *proxy) post(r *http.Request) (response, error) {
+	payload, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	if err != nil {
+		status := http.StatusRequestEntityTooLarge
+
+		return response{status: status, body: errorBody(status, "Request too large")}, nil
+	}
+
+	body, status, err := p.client.Raw(r.Context(), http.MethodPost, r.URL.RequestURI(), payload)
+
+	return response{body: body, status: status}, err
+}
+
+func writeJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(body) //nolint: errcheck
+}
+
+// writeError writes an error in the format of postcodes.io errors.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorBody(status, message))
+}
+
+func errorBody(status int, message string) []byte {
+	b, _ := json.Marshal(struct { //nolint: errchkjson
+		Status int    `json:"status"`
+		Error  string `json:"error"`
+	}{status, message})
+
+	return b
+}
+
+// flight is an upstream request shared by coalesced callers.
+type flight struct {
+	wg  sync.WaitGroup
+	res response
+	err error
+}
+
+// flightGroup coalesces concurrent calls with the same key into a single call.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// do calls fn, unless a call with the same key is in flight, in which case it waits for and returns its result.
+// shared reports whether the result was shared with another caller.
+func (g *flightGroup) do(key string, fn func() (response, error)) (res response, err error, shared bool) { //nolint: revive
+	g.mu.Lock()
+
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		f.wg.Wait()
+
+		return f.res, f.err, true
+	}
+
+	f := &flight{}
+	f.wg.Add(1)
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	f.res, f.err = fn()
+	f.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return f.res, f.err, false
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const lookupBody = `{"status":200,"result":{"postcode":"NW1 6XE","quality":1,"admin_county":null}}`
+
+func newUpstream(t *testing.T, calls *atomic.Int32, delay time.Duration) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(delay)
+
+		switch {
+		case r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			fmt.Fprintf(w, `{"status":200,"uri":%q,"body":%s}`, r.RequestURI, body)
+		case r.URL.Path == "/postcodes/NW1 6XE":
+			fmt.Fprint(w, lookupBody)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"status":404,"error":"Postcode not found"}`)
+		}
+	}))
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+
+	res, err := http.Get(url) //nolint: noctx
+	assert.NoError(t, err)
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	return res.StatusCode, string(body)
+}
+
+func TestProxy_Get(t *testing.T) {
+	var calls atomic.Int32
+
+	upstream := newUpstream(t, &calls, 0)
+	defer upstream.Close()
+
+	srv := httptest.NewServer(newProxy(postcodesio.New(postcodesio.WithBaseURL(upstream.URL)), 10, time.Minute))
+	defer srv.Close()
+
+	for i := 0; i < 2; i++ {
+		status, body := get(t, srv.URL+"/postcodes/NW1%206XE")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, lookupBody, body)
+	}
+
+	assert.EqualValues(t, 1, calls.Load(), "second request must be served from the cache")
+
+	for i := 0; i < 2; i++ {
+		status, body := get(t, srv.URL+"/postcodes/XX11XX")
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, `{"status":404,"error":"Postcode not found"}`, body)
+	}
+
+	assert.EqualValues(t, 3, calls.Load(), "errors must not be cached")
+
+	_, metrics := get(t, srv.URL+"/metrics")
+	assert.Contains(t, metrics, `postcodesio_proxy_requests_total{method="GET",code="200"} 2`)
+	assert.Contains(t, metrics, `postcodesio_proxy_requests_total{method="GET",code="404"} 2`)
+	assert.Contains(t, metrics, "postcodesio_proxy_cache_hits_total 1")
+	assert.Contains(t, metrics, "postcodesio_proxy_cache_misses_total 3")
+}
+
+func TestProxy_Post(t *testing.T) {
+	var calls atomic.Int32
+
+	upstream := newUpstream(t, &calls, 0)
+	defer upstream.Close()
+
+	srv := httptest.NewServer(newProxy(postcodesio.New(postcodesio.WithBaseURL(upstream.URL)), 10, time.Minute))
+	defer srv.Close()
+
+	payload := strings.NewReader(`{"postcodes":["NW1 6XE"]}`)
+
+	res, err := http.Post(srv.URL+"/postcodes?filter=postcode", "application/json", payload) //nolint: noctx
+	assert.NoError(t, err)
+
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, `{"status":200,"uri":"/postcodes?filter=postcode","body":{"postcodes":["NW1 6XE"]}}`, string(body))
+}
+
+func TestProxy_Coalescing(t *testing.T) {
+	var calls atomic.Int32
+
+	upstream := newUpstream(t, &calls, 100*time.Millisecond)
+	defer upstream.Close()
+
+	srv := httptest.NewServer(newProxy(postcodesio.New(postcodesio.WithBaseURL(upstream.URL)), 0, time.Minute))
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			status, body := get(t, srv.URL+"/postcodes/NW1%206XE")
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, lookupBody, body)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestProxy_UpstreamDown(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+
+	srv := httptest.NewServer(newProxy(postcodesio.New(postcodesio.WithBaseURL(upstream.URL)), 10, time.Minute))
+	defer srv.Close()
+
+	status, body := get(t, srv.URL+"/postcodes/NW1%206XE")
+	assert.Equal(t, http.StatusBadGateway, status)
+	assert.Equal(t, `{"status":502,"error":"Upstream request failed"}`, body)
+
+	_, metrics := get(t, srv.URL+"/metrics")
+	assert.Contains(t, metrics, "postcodesio_proxy_upstream_errors_total 1")
+}
+
+func TestProxy_MetricsUnknownMethods(t *testing.T) {
+	srv := httptest.NewServer(newProxy(postcodesio.New(), 10, time.Minute))
+	defer srv.Close()
+
+	for _, method := range []string{"FOO", "BAR", http.MethodDelete} {
+		req, err := http.NewRequestWithContext(context.Background(), method, srv.URL+"/postcodes", nil)
+		assert.NoError(t, err)
+
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+
+		res.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	}
+
+	_, metrics := get(t, srv.URL+"/metrics")
+	assert.Contains(t, metrics, `postcodesio_proxy_requests_total{method="other",code="405"} 2`)
+	assert.Contains(t, metrics, `postcodesio_proxy_requests_total{method="DELETE",code="405"} 1`)
+	assert.NotContains(t, metrics, "FOO")
+}
+
+func TestProxy_Health(t *testing.T) {
+	srv := httptest.NewServer(newProxy(postcodesio.New(), 10, time.Minute))
+	defer srv.Close()
+
+	status, body := get(t, srv.URL+"/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"status":200,"result":{"circuit":"closed"}}`, body)
+}
+
+func TestProxy_HealthCircuitOpen(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	client := postcodesio.New(
+		postcodesio.WithBaseURL(upstream.URL),
+		postcodesio.WithCircuitBreaker(postcodesio.CircuitBreakerConfig{FailureThreshold: 1, CoolDown: time.Minute}),
+	)
+
+	srv := httptest.NewServer(newProxy(client, 10, time.Minute))
+	defer srv.Close()
+
+	get(t, srv.URL+"/postcodes/NW1%206XE")
+
+	status, body := get(t, srv.URL+"/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, `{"status":503,"result":{"circuit":"open"}}`, body)
+}
+
+func TestRateLimitedTransport(t *testing.T) {
+	var calls atomic.Int32
+
+	upstream := newUpstream(t, &calls, 0)
+	defer upstream.Close()
+
+	client := postcodesio.New(
+		postcodesio.WithBaseURL(upstream.URL),
+		postcodesio.WithTransport(rateLimitedTransport{next: http.DefaultTransport, limiter: newRateLimiter(20, 2)}),
+	)
+
+	start := time.Now()
+
+	for i := 0; i < 6; i++ {
+		_, _, err := client.Raw(context.Background(), http.MethodGet, "/postcodes/NW1%206XE", nil)
+		assert.NoError(t, err)
+	}
+
+	// A burst of 2, then 4 requests spaced by 50ms.
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.EqualValues(t, 6, calls.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	limiter := newRateLimiter(1, 1)
+	assert.NoError(t, limiter.wait(ctx))
+	assert.ErrorIs(t, limiter.wait(ctx), context.Canceled)
+}
@@ -0,0 +1,290 @@
+package postcodesio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvBaseURL          = "POSTCODESIO_BASE_URL"
+	EnvFallbackURLs     = "POSTCODESIO_FALLBACK_URLS"
+	EnvEndpointCooldown = "POSTCODESIO_ENDPOINT_COOLDOWN"
+	EnvTimeout          = "POSTCODESIO_TIMEOUT"
+	EnvProxyURL         = "POSTCODESIO_PROXY_URL"
+	EnvUserAgent        = "POSTCODESIO_USER_AGENT"
+	EnvAPIKey           = "POSTCODESIO_API_KEY"
+	EnvAPIKeyHeader     = "POSTCODESIO_API_KEY_HEADER"
+	EnvMaxResponseSize  = "POSTCODESIO_MAX_RESPONSE_SIZE"
+	EnvBreakerThreshold = "POSTCODESIO_BREAKER_THRESHOLD"
+	EnvBreakerCoolDown  = "POSTCODESIO_BREAKER_COOLDOWN"
+	EnvHedgeDelay       = "POSTCODESIO_HEDGE_DELAY"
+	EnvHedgePercentile  = "POSTCODESIO_HEDGE_PERCENTILE"
+	EnvMaxHedges        = "POSTCODESIO_MAX_HEDGES"
+)
+
+const (
+	defaultAPIKeyHeader = "X-Api-Key"
+	maxPercentile       = 100
+)
+
+// Config is the configuration of a Client created with NewFromConfig. Zero values keep the Client defaults.
+// FallbackURLs are only used with BaseURL. ProxyURL sets the HTTP proxy, which is otherwise read from the
+// HTTP_PROXY and HTTPS_PROXY environment variables. APIKeyHeader defaults to X-Api-Key. CircuitBreaker and Hedging
+// are disabled when nil.
+type Config struct {
+	BaseURL          string
+	FallbackURLs     []string
+	EndpointCooldown time.Duration
+	Timeout          time.Duration
+	ProxyURL         string
+	UserAgent        string
+	APIKey           string
+	APIKeyHeader     string
+	MaxResponseSize  int64
+	CircuitBreaker   *CircuitBreakerConfig
+	Hedging          *HedgePolicy
+}
+
+// NewFromConfig validates cfg and creates a Client configured by it. opts are applied after the configuration, and
+// take precedence over it. All the invalid values of cfg are reported in the returned error.
+func NewFromConfig(cfg Config, opts ...ClientOption) (*Client, error) {
+	cfgOpts, err := cfg.options()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(append(cfgOpts, opts...)...), nil
+}
+
+// NewFromEnv creates a Client configured by the POSTCODESIO_* environment variables, see ConfigFromEnv.
+// opts are applied after the configuration, and take precedence over it.
+func NewFromEnv(opts ...ClientOption) (*Client, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromConfig(cfg, opts...)
+}
+
+// ConfigFromEnv reads a Config from the POSTCODESIO_* environment variables. Durations use the time.ParseDuration
+// format, e.g. "10s", and lists are comma separated. The circuit breaker is enabled by POSTCODESIO_BREAKER_THRESHOLD
+// and hedging by POSTCODESIO_HEDGE_DELAY. All the invalid values are reported in the returned error.
+func ConfigFromEnv() (Config, error) {
+	p := envParser{}
+
+	cfg := Config{
+		BaseURL:          os.Getenv(EnvBaseURL),
+		FallbackURLs:     p.list(EnvFallbackURLs),
+		EndpointCooldown: p.duration(EnvEndpointCooldown),
+		Timeout:          p.duration(EnvTimeout),
+		ProxyURL:         os.Getenv(EnvProxyURL),
+		UserAgent:        os.Getenv(EnvUserAgent),
+		APIKey:           os.Getenv(EnvAPIKey),
+		APIKeyHeader:     os.Getenv(EnvAPIKeyHeader),
+		MaxResponseSize:  int64(p.int(EnvMaxResponseSize)),
+	}
+
+	if _, ok := os.LookupEnv(EnvBreakerThreshold); ok {
+		cfg.CircuitBreaker = &CircuitBreakerConfig{
+			FailureThreshold: p.int(EnvBreakerThreshold),
+			CoolDown:         p.duration(EnvBreakerCoolDown),
+		}
+	}
+
+	if _, ok := os.LookupEnv(EnvHedgeDelay); ok {
+		cfg.Hedging = &HedgePolicy{
+			Delay:      p.duration(EnvHedgeDelay),
+			Percentile: p.float(EnvHedgePercentile),
+			MaxHedges:  p.int(EnvMaxHedges),
+		}
+	}
+
+	if err := errors.Join(p.errs...); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// options validates the configuration and returns the options applying it.
+func (cfg Config) options() ([]ClientOption, error) {
+	var (
+		opts []ClientOption
+		errs []error
+	)
+
+	invalid := func(field string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s %s", ErrInvalidConfig, field, fmt.Sprintf(format, args...)))
+	}
+
+	if cfg.BaseURL != "" {
+		field := "BaseURL"
+
+		for _, u := range append([]string{cfg.BaseURL}, cfg.FallbackURLs...) {
+			if !validURL(u) {
+				invalid(field, "%q is not an absolute http or https URL", u)
+			}
+
+			field = "FallbackURLs"
+		}
+
+		opts = append(opts, WithEndpoints(cfg.BaseURL, cfg.FallbackURLs...))
+	} else if len(cfg.FallbackURLs) > 0 {
+		invalid("FallbackURLs", "set without BaseURL")
+	}
+
+	if cfg.EndpointCooldown < 0 {
+		invalid("EndpointCooldown", "must not be negative, got %s", cfg.EndpointCooldown)
+	} else if cfg.EndpointCooldown > 0 {
+		opts = append(opts, WithEndpointCooldown(cfg.EndpointCooldown))
+	}
+
+	if cfg.Timeout < 0 {
+		invalid("Timeout", "must not be negative, got %s", cfg.Timeout)
+	} else if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(cfg.Timeout))
+	}
+
+	if cfg.ProxyURL != "" {
+		// The proxy URL is not part of the error, as it may include credentials.
+		if !validURL(cfg.ProxyURL) {
+			invalid("ProxyURL", "is not an absolute http or https URL")
+		} else {
+			proxy, _ := url.Parse(cfg.ProxyURL)
+			transport := http.DefaultTransport.(*http.Transport).Clone() //nolint: forcetypeassert
+			transport.Proxy = http.ProxyURL(proxy)
+			opts = append(opts, WithTransport(transport))
+		}
+	}
+
+	if cfg.UserAgent != "" {
+		opts = append(opts, WithUserAgent(cfg.UserAgent))
+	}
+
+	switch {
+	case cfg.APIKey != "":
+		header := cfg.APIKeyHeader
+		if header == "" {
+			header = defaultAPIKeyHeader
+		}
+
+		opts = append(opts, WithAPIKey(header, cfg.APIKey))
+	case cfg.APIKeyHeader != "":
+		invalid("APIKeyHeader", "set without APIKey")
+	}
+
+	if cfg.MaxResponseSize < 0 {
+		invalid("MaxResponseSize", "must not be negative, got %d", cfg.MaxResponseSize)
+	} else if cfg.MaxResponseSize > 0 {
+		opts = append(opts, WithMaxResponseSize(cfg.MaxResponseSize))
+	}
+
+	if b := cfg.CircuitBreaker; b != nil {
+		if b.FailureThreshold < 0 || b.CoolDown < 0 || b.HalfOpenMaxRequests < 0 {
+			invalid("CircuitBreaker", "values must not be negative")
+		}
+
+		opts = append(opts, WithCircuitBreaker(*b))
+	}
+
+	if h := cfg.Hedging; h != nil {
+		if h.Delay <= 0 && h.Percentile <= 0 {
+			invalid("Hedging", "requires a positive Delay or Percentile")
+		}
+
+		if h.Delay < 0 || h.MaxHedges < 0 || h.Budget < 0 || h.Percentile < 0 || h.Percentile > maxPercentile {
+			invalid("Hedging", "values must not be negative, and Percentile must not exceed 100")
+		}
+
+		opts = append(opts, WithHedging(*h))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// validURL reports whether u is an absolute http or https URL.
+func validURL(u string) bool {
+	parsed, err := url.Parse(u)
+
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// envParser parses environment variables, collecting the errors of the invalid ones.
+type envParser struct {
+	errs []error
+}
+
+func (p *envParser) fail(name, value, reason string) {
+	p.errs = append(p.errs, fmt.Errorf("%w: %s=%q is not a valid %s", ErrInvalidConfig, name, value, reason))
+}
+
+func (p *envParser) list(name string) []string {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func (p *envParser) duration(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		p.fail(name, value, "duration")
+	}
+
+	return d
+}
+
+func (p *envParser) int(name string) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail(name, value, "integer")
+	}
+
+	return n
+}
+
+func (p *envParser) float(name string) float64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.fail(name, value, "number")
+	}
+
+	return f
+}
@@ -0,0 +1,130 @@
+package postcodesio_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/postcodes/NW16XE", r.URL.Path)
+		assert.Equal(t, "my-app/1.0", r.Header.Get("User-Agent"))
+		assert.Equal(t, "Bearer k3y", r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"status":200}`)
+	}))
+	defer srv.Close()
+
+	t.Setenv(postcodesio.EnvBaseURL, "http://127.0.0.1:1")
+	t.Setenv(postcodesio.EnvFallbackURLs, " "+srv.URL+" ,")
+	t.Setenv(postcodesio.EnvTimeout, "5s")
+	t.Setenv(postcodesio.EnvUserAgent, "my-app/1.0")
+	t.Setenv(postcodesio.EnvAPIKey, "Bearer k3y")
+	t.Setenv(postcodesio.EnvAPIKeyHeader, "Authorization")
+	t.Setenv(postcodesio.EnvBreakerThreshold, "3")
+	t.Setenv(postcodesio.EnvHedgeDelay, "50ms")
+
+	cfg, err := postcodesio.ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{srv.URL}, cfg.FallbackURLs)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, &postcodesio.CircuitBreakerConfig{FailureThreshold: 3}, cfg.CircuitBreaker)
+	assert.Equal(t, &postcodesio.HedgePolicy{Delay: 50 * time.Millisecond}, cfg.Hedging)
+
+	c, err := postcodesio.NewFromEnv()
+	assert.NoError(t, err)
+
+	res, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.Status)
+}
+
+func TestConfigFromEnv_Invalid(t *testing.T) {
+	t.Setenv(postcodesio.EnvTimeout, "5 seconds")
+	t.Setenv(postcodesio.EnvMaxResponseSize, "1MB")
+	t.Setenv(postcodesio.EnvHedgeDelay, "50ms")
+	t.Setenv(postcodesio.EnvHedgePercentile, "p95")
+
+	_, err := postcodesio.NewFromEnv()
+	assert.ErrorIs(t, err, postcodesio.ErrInvalidConfig)
+	assert.ErrorContains(t, err, `POSTCODESIO_TIMEOUT="5 seconds" is not a valid duration`)
+	assert.ErrorContains(t, err, `POSTCODESIO_MAX_RESPONSE_SIZE="1MB" is not a valid integer`)
+	assert.ErrorContains(t, err, `POSTCODESIO_HEDGE_PERCENTILE="p95" is not a valid number`)
+}
+
+func TestNewFromConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      postcodesio.Config
+		expected []string
+	}{
+		{
+			name:     "relative base URL and bad fallback",
+			cfg:      postcodesio.Config{BaseURL: "api.postcodes.io", FallbackURLs: []string{"ftp://mirror"}},
+			expected: []string{`BaseURL "api.postcodes.io" is not`, `FallbackURLs "ftp://mirror" is not`},
+		},
+		{
+			name:     "fallbacks without base URL",
+			cfg:      postcodesio.Config{FallbackURLs: []string{"https://mirror"}},
+			expected: []string{"FallbackURLs set without BaseURL"},
+		},
+		{
+			name:     "negative values",
+			cfg:      postcodesio.Config{Timeout: -time.Second, MaxResponseSize: -1, EndpointCooldown: -time.Second},
+			expected: []string{"Timeout must not be negative", "MaxResponseSize must not be negative", "EndpointCooldown must not"},
+		},
+		{
+			name:     "proxy URL with credentials",
+			cfg:      postcodesio.Config{ProxyURL: "//user:hunter2@proxy"},
+			expected: []string{"ProxyURL is not an absolute http or https URL"},
+		},
+		{
+			name:     "API key header without key",
+			cfg:      postcodesio.Config{APIKeyHeader: "X-Api-Key"},
+			expected: []string{"APIKeyHeader set without APIKey"},
+		},
+		{
+			name: "resilience",
+			cfg: postcodesio.Config{
+				CircuitBreaker: &postcodesio.CircuitBreakerConfig{FailureThreshold: -1},
+				Hedging:        &postcodesio.HedgePolicy{Percentile: 120},
+			},
+			expected: []string{"CircuitBreaker values must not be negative", "Percentile must not exceed 100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := postcodesio.NewFromConfig(tt.cfg)
+			assert.Nil(t, c)
+			assert.ErrorIs(t, err, postcodesio.ErrInvalidConfig)
+
+			for _, expected := range tt.expected {
+				assert.ErrorContains(t, err, expected)
+			}
+
+			assert.NotContains(t, err.Error(), "hunter2")
+		})
+	}
+}
+
+func TestNewFromConfig_ProxyURL(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "postcodes.example", r.URL.Host)
+		fmt.Fprint(w, `{"status":200}`)
+	}))
+	defer proxy.Close()
+
+	c, err := postcodesio.NewFromConfig(postcodesio.Config{BaseURL: "http://postcodes.example", ProxyURL: proxy.URL})
+	assert.NoError(t, err)
+
+	res, err := c.PostcodeLookup(context.Background(), "NW16XE")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.Status)
+}
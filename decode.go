@@ -0,0 +1,81 @@
+package postcodesio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// decodeResults decodes a response object from r, calling fn with each item of its result array as soon as the
+// item is decoded, so that the whole result is never held in memory. It returns the status of the response.
+func decodeResults[T any](r io.Reader, fn func(T) error) (int, error) {
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return 0, err
+	}
+
+	var status int
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return 0, err
+		}
+
+		switch key {
+		case "status":
+			err = dec.Decode(&status)
+		case "result":
+			err = decodeItems(dec, fn)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return status, expectDelim(dec, '}')
+}
+
+// decodeItems decodes the array, or null, at the position of dec, calling fn with each of its items.
+func decodeItems[T any](dec *json.Decoder, fn func(T) error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+
+	if tok != json.Delim('[') {
+		return fmt.Errorf("%w: result is %v, not an array", ErrUnexpectedResponse, tok)
+	}
+
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, ']')
+}
+
+// expectDelim reads the next token of dec, failing if it is not delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok != delim {
+		return fmt.Errorf("%w: got %v, want %v", ErrUnexpectedResponse, tok, delim)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package postcodesio
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"slices"
+
+	"github.com/leandrorondon/postcodesio-go/geo"
+)
+
+// DistanceMatrix is the matrix of straight-line distances between two sets of postcodes.
+// Distances[i][j] is the haversine distance in metres from Origins[i] to Destinations[j], or NaN if either of them is
+// unresolved. Unresolved lists, once each and in input order, the postcodes not found or without coordinates.
+// In JSON, NaN distances are null.
+type DistanceMatrix struct {
+	Origins      []string    `json:"origins"`
+	Destinations []string    `json:"destinations"`
+	Distances    [][]float64 `json:"distances"`
+	Unresolved   []string    `json:"unresolved"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding NaN distances as null.
+func (m DistanceMatrix) MarshalJSON() ([]byte, error) {
+	type plain DistanceMatrix
+
+	distances := make([][]*float64, len(m.Distances))
+
+	for i, row := range m.Distances {
+		distances[i] = make([]*float64, len(row))
+
+		for j := range row {
+			if !math.IsNaN(row[j]) {
+				distances[i][j] = &row[j]
+			}
+		}
+	}
+
+	return json.Marshal(struct {
+		plain
+		Distances [][]*float64 `json:"distances"`
+	}{plain(m), distances})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding null distances as NaN.
+func (m *DistanceMatrix) UnmarshalJSON(data []byte) error {
+	type plain DistanceMatrix
+
+	var v struct {
+		plain
+		Distances [][]*float64 `json:"distances"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*m = DistanceMatrix(v.plain)
+	m.Distances = make([][]float64, len(v.Distances))
+
+	for i, row := range v.Distances {
+		m.Distances[i] = make([]float64, len(row))
+
+		for j, d := range row {
+			m.Distances[i][j] = math.NaN()
+			if d != nil {
+				m.Distances[i][j] = *d
+			}
+		}
+	}
+
+	return nil
+}
+
+// DistanceMatrix looks up the origins and destinations, each distinct postcode once, with chunked bulk lookups, and
+// returns the matrix of straight-line distances between them. Postcodes are matched case and space insensitively.
+// Filters set with WithFilter always include the postcode and its coordinates.
+func (c *Client) DistanceMatrix(
+	ctx context.Context, origins, destinations []string, opts ...RequestOption,
+) (*DistanceMatrix, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	in := make(chan string)
+
+	go func() {
+		defer close(in)
+
+		seen := make(map[string]bool)
+
+		for _, postcode := range append(append([]string(nil), origins...), destinations...) {
+			key := NormalizePostcode(postcode)
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+
+			select {
+			case in <- postcode:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	resolved := make(map[string]Postcode)
+	opts = append(slices.Clip(opts), WithFilter(requiredFilters(opts, FieldLatitude, FieldLongitude)...))
+
+	for r := range c.LookupStream(ctx, in, WithStreamRequestOptions(opts...)) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		if r.Result != nil {
+			resolved[NormalizePostcode(r.Query)] = *r.Result
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m := &DistanceMatrix{Origins: origins, Destinations: destinations}
+	unresolved := make(map[string]bool)
+
+	resolve := func(postcodes []string) []Postcode {
+		results := make([]Postcode, len(postcodes))
+
+		for i, postcode := range postcodes {
+			key := NormalizePostcode(postcode)
+			results[i] = resolved[key]
+
+			if _, _, ok := results[i].Location(); !ok && !unresolved[key] {
+				unresolved[key] = true
+				m.Unresolved = append(m.Unresolved, postcode)
+			}
+		}
+
+		return results
+	}
+
+	m.Distances = Distances(resolve(origins), resolve(destinations))
+
+	return m, nil
+}
+
+// Distances returns the matrix of haversine distances in metres from each origin to each destination.
+// Distances from or to a postcode without coordinates are NaN.
+func Distances(origins, destinations []Postcode) [][]float64 {
+	points := func(postcodes []Postcode) []*geo.Point {
+		result := make([]*geo.Point, len(postcodes))
+
+		for i, p := range postcodes {
+			if lat, lon, ok := p.Location(); ok {
+				result[i] = &geo.Point{Latitude: lat, Longitude: lon}
+			}
+		}
+
+		return result
+	}
+
+	from, to := points(origins), points(destinations)
+	distances := make([][]float64, len(from))
+
+	for i, a := range from {
+		distances[i] = make([]float64, len(to))
+
+		for j, b := range to {
+			if a == nil || b == nil {
+				distances[i][j] = math.NaN()
+
+				continue
+			}
+
+			distances[i][j] = geo.Distance(*a, *b)
+		}
+	}
+
+	return distances
+}
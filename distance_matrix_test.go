@@ -0,0 +1,134 @@
+package postcodesio_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceMatrix(t *testing.T) {
+	coordinates := map[string]string{
+		"SW1A2AA": `"latitude":51.50354,"longitude":-0.127695`,
+		"NW16XE":  `"latitude":51.523659,"longitude":-0.158541`,
+		"M11AE":   `"latitude":53.480687,"longitude":-2.238442`,
+		"GY11AA":  `"latitude":null,"longitude":null`,
+	}
+
+	var queried atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req postcodesio.BulkPostCodeLookupRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		queried.Add(int32(len(req.Postcodes)))
+
+		results := make([]string, len(req.Postcodes))
+		for i, p := range req.Postcodes {
+			results[i] = fmt.Sprintf(`{"query":%q,"result":null}`, p)
+
+			if c, ok := coordinates[postcodesio.NormalizePostcode(p)]; ok {
+				results[i] = fmt.Sprintf(`{"query":%q,"result":{"postcode":%q,%s}}`, p, p, c)
+			}
+		}
+
+		fmt.Fprintf(w, `{"status":200,"result":[%s]}`, strings.Join(results, ","))
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+	origins := []string{"SW1A 2AA", "nw1 6xe", "XX1 1XX"}
+	destinations := []string{"M1 1AE", "SW1A2AA", "GY1 1AA", "xx11xx"}
+
+	m, err := c.DistanceMatrix(context.Background(), origins, destinations)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, queried.Load(), "each distinct postcode must be looked up once")
+	assert.Equal(t, origins, m.Origins)
+	assert.Equal(t, destinations, m.Destinations)
+	assert.Equal(t, []string{"XX1 1XX", "GY1 1AA"}, m.Unresolved)
+
+	assert.Len(t, m.Distances, 3)
+	assert.InDelta(t, 262900, m.Distances[0][0], 1000)
+	assert.Zero(t, m.Distances[0][1])
+	assert.InDelta(t, 3092, m.Distances[1][1], 1)
+	assert.True(t, math.IsNaN(m.Distances[0][2]))
+	assert.True(t, math.IsNaN(m.Distances[1][3]))
+
+	for _, d := range m.Distances[2] {
+		assert.True(t, math.IsNaN(d))
+	}
+}
+
+func TestDistanceMatrix_JSON(t *testing.T) {
+	m := postcodesio.DistanceMatrix{
+		Origins:      []string{"SW1A 2AA", "XX1 1XX"},
+		Destinations: []string{"NW1 6XE"},
+		Distances:    [][]float64{{3092.5}, {math.NaN()}},
+		Unresolved:   []string{"XX1 1XX"},
+	}
+
+	b, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"origins": ["SW1A 2AA", "XX1 1XX"],
+		"destinations": ["NW1 6XE"],
+		"distances": [[3092.5], [null]],
+		"unresolved": ["XX1 1XX"]
+	}`, string(b))
+
+	var decoded postcodesio.DistanceMatrix
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, m.Origins, decoded.Origins)
+	assert.Equal(t, m.Destinations, decoded.Destinations)
+	assert.Equal(t, m.Unresolved, decoded.Unresolved)
+	assert.Equal(t, 3092.5, decoded.Distances[0][0])
+	assert.True(t, math.IsNaN(decoded.Distances[1][0]))
+}
+
+func TestDistanceMatrix_Filter(t *testing.T) {
+	srv := newFilteringLookupServer(t, map[string]map[string]any{
+		"SW1A2AA": {"postcode": "SW1A 2AA", "country": "England", "latitude": 51.50354, "longitude": -0.127695},
+		"NW16XE":  {"postcode": "NW1 6XE", "country": "England", "latitude": 51.523659, "longitude": -0.158541},
+	})
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	m, err := c.DistanceMatrix(context.Background(), []string{"NW1 6XE"}, []string{"SW1A 2AA"},
+		postcodesio.WithFilter(postcodesio.FieldCountry))
+	assert.NoError(t, err)
+	assert.Empty(t, m.Unresolved)
+	assert.InDelta(t, 3092, m.Distances[0][0], 1)
+}
+
+func TestDistanceMatrix_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"status":500,"error":"boom"}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	_, err := c.DistanceMatrix(context.Background(), []string{"SW1A 2AA"}, []string{"NW1 6XE"})
+	assert.ErrorIs(t, err, postcodesio.ErrUnexpectedStatus)
+}
+
+func TestDistances(t *testing.T) {
+	a := postcodesio.Postcode{Latitude: postcodesio.Some(51.50354), Longitude: postcodesio.Some(-0.127695)}
+	b := postcodesio.Postcode{Latitude: postcodesio.Some(51.523659), Longitude: postcodesio.Some(-0.158541)}
+
+	d := postcodesio.Distances([]postcodesio.Postcode{a, b}, []postcodesio.Postcode{b, {}})
+	assert.Len(t, d, 2)
+	assert.InDelta(t, 3092, d[0][0], 1)
+	assert.Zero(t, d[1][0])
+	assert.True(t, math.IsNaN(d[0][1]))
+	assert.True(t, math.IsNaN(d[1][1]))
+}
@@ -0,0 +1,83 @@
+package postcodesio
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultEndpointCooldown = 30 * time.Second
+
+// endpoint is a postcodes.io base URL and its health.
+type endpoint struct {
+	url       string
+	downUntil time.Time
+}
+
+// endpointPool tracks the health of the endpoints a Client fails over between.
+// An endpoint that fails is skipped for a cooldown period, after which the next request probes it again.
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+func newEndpointPool(urls ...string) *endpointPool {
+	p := &endpointPool{
+		cooldown: defaultEndpointCooldown,
+		now:      time.Now,
+	}
+	p.set(urls...)
+
+	return p
+}
+
+// set replaces the endpoints of the pool.
+func (p *endpointPool) set(urls ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.endpoints = make([]*endpoint, len(urls))
+	for i, u := range urls {
+		p.endpoints[i] = &endpoint{url: strings.TrimRight(u, "/")}
+	}
+}
+
+// candidates returns the endpoints to try, in order of preference. Healthy endpoints come first, in the configured
+// order, followed by the endpoints in cooldown, so that a request is still attempted when every endpoint is down.
+func (p *endpointPool) candidates() []*endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	healthy := make([]*endpoint, 0, len(p.endpoints))
+
+	var down []*endpoint
+
+	for _, e := range p.endpoints {
+		if now.Before(e.downUntil) {
+			down = append(down, e)
+		} else {
+			healthy = append(healthy, e)
+		}
+	}
+
+	return append(healthy, down...)
+}
+
+// markDown puts the endpoint in cooldown.
+func (p *endpointPool) markDown(e *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.downUntil = p.now().Add(p.cooldown)
+}
+
+// markUp marks the endpoint as healthy.
+func (p *endpointPool) markUp(e *endpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.downUntil = time.Time{}
+}
@@ -0,0 +1,28 @@
+package postcodesio
+
+import "errors"
+
+var (
+	// ErrUnknownField is returned when a filter references a field that is not a Postcode attribute.
+	ErrUnknownField = errors.New("unknown filter field")
+	// ErrCircuitOpen is returned when a request is not sent because the circuit breaker is open.
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+	// ErrUnexpectedStatus is returned when the API responds with an unexpected status.
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+	// ErrJobIncomplete is returned when some chunks of a BulkJob failed.
+	ErrJobIncomplete = errors.New("bulk job incomplete")
+	// ErrCheckpointMismatch is returned when a checkpoint file was created by a different BulkJob.
+	ErrCheckpointMismatch = errors.New("checkpoint does not match the bulk job")
+	// ErrInvalidPostcode is returned when a postcode does not follow the UK postcode format.
+	ErrInvalidPostcode = errors.New("invalid postcode format")
+	// ErrResponseTooLarge is returned when a response body exceeds the maximum response size.
+	ErrResponseTooLarge = errors.New("response too large")
+	// ErrUnexpectedResponse is returned when a response body does not have the expected structure.
+	ErrUnexpectedResponse = errors.New("unexpected response")
+	// ErrInvalidConfig is returned when a Config, or the environment variables it is read from, has invalid values.
+	ErrInvalidConfig = errors.New("invalid configuration")
+	// ErrSearchTruncated is returned with the postcodes found when an area search could not list every postcode.
+	ErrSearchTruncated = errors.New("search truncated")
+	// ErrInvalidRequest is matched by the *ValidationError returned when a request does not satisfy the API constraints.
+	ErrInvalidRequest = errors.New("invalid request")
+)
@@ -33,7 +33,9 @@ func bulkPostCodeLookup(c *postcodesio.Client) {
 func bulkPostCodeLookupWithFilters(c *postcodesio.Client) {
 	bulkRequest := postcodesio.BulkPostCodeLookupRequest{
 		Postcodes: []string{"NW1 6XE"},
-		Filters:   []string{"postcode", "country", "longitude", "latitude"},
+		Filters: []postcodesio.Field{
+			postcodesio.FieldPostcode, postcodesio.FieldCountry, postcodesio.FieldLongitude, postcodesio.FieldLatitude,
+		},
 	}
 	res, err := c.BulkPostcodeLookup(context.Background(), bulkRequest)
 	if err != nil {
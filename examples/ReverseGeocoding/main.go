@@ -13,6 +13,7 @@ func main() {
 
 	reverseGeocoding(client)
 	reverseGeocodingWithExtras(client)
+	bulkReverseGeocoding(client)
 
 }
 
@@ -48,3 +49,21 @@ func reverseGeocodingWithExtras(c *postcodesio.Client) {
 		fmt.Printf("%#v\n", r)
 	}
 }
+
+func bulkReverseGeocoding(c *postcodesio.Client) {
+	request := postcodesio.BulkReverseGeocodingRequest{
+		Geolocations: []postcodesio.Geolocation{
+			{Longitude: -0.158541, Latitude: 51.523659},
+			{Longitude: -0.124625, Latitude: 51.499840, Limit: 1},
+		},
+		Filters: []postcodesio.Field{postcodesio.FieldPostcode},
+	}
+	res, err := c.BulkReverseGeocoding(context.Background(), request)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, r := range res.Result {
+		fmt.Printf("%#v: %#v\n", r.Query, r.Result)
+	}
+}
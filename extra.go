@@ -0,0 +1,96 @@
+package postcodesio
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldNamesCache caches the JSON field names of the types decoded with unmarshalWithExtra.
+var fieldNamesCache sync.Map // map[reflect.Type]map[string]bool
+
+// unmarshalWithExtra decodes data into v, which must be a pointer to a struct type without its own UnmarshalJSON
+// method, and returns the JSON members that did not match any of its fields.
+func unmarshalWithExtra(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	for name := range members {
+		if known[name] {
+			delete(members, name)
+		}
+	}
+
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	return members, nil
+}
+
+// marshalWithExtra encodes v and merges the extra members into the resulting JSON object.
+// Members of v take precedence over extra members with the same name.
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(b, &members); err != nil {
+		return nil, err
+	}
+
+	for name, value := range extra {
+		if _, ok := members[name]; !ok {
+			members[name] = value
+		}
+	}
+
+	return json.Marshal(members)
+}
+
+// jsonFieldNames returns the JSON member names of the fields of a struct type, including promoted fields.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if names, ok := fieldNamesCache.Load(t); ok {
+		return names.(map[string]bool) //nolint: forcetypeassert
+	}
+
+	names := make(map[string]bool)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			for name := range jsonFieldNames(field.Type) {
+				names[name] = true
+			}
+
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		switch {
+		case name == "-" || !field.IsExported():
+			continue
+		case name == "":
+			names[field.Name] = true
+		default:
+			names[name] = true
+		}
+	}
+
+	fieldNamesCache.Store(t, names)
+
+	return names
+}
@@ -0,0 +1,93 @@
+package postcodesio
+
+import "strings"
+
+// Field is a Postcode attribute used to filter the attributes returned by the API.
+type Field string
+
+// Postcode attributes that can be used as filters.
+const (
+	FieldPostcode                      Field = "postcode"
+	FieldOutcode                       Field = "outcode"
+	FieldIncode                        Field = "incode"
+	FieldQuality                       Field = "quality"
+	FieldEastings                      Field = "eastings"
+	FieldNorthings                     Field = "northings"
+	FieldCountry                       Field = "country"
+	FieldNHSHA                         Field = "nhs_ha"
+	FieldAdminCounty                   Field = "admin_county"
+	FieldAdminDistrict                 Field = "admin_district"
+	FieldAdminWard                     Field = "admin_ward"
+	FieldLongitude                     Field = "longitude"
+	FieldLatitude                      Field = "latitude"
+	FieldParliamentaryConstituency     Field = "parliamentary_constituency"
+	FieldParliamentaryConstituency2024 Field = "parliamentary_constituency_2024"
+	FieldEuropeanElectoralRegion       Field = "european_electoral_region"
+	FieldPrimaryCareTrust              Field = "primary_care_trust"
+	FieldRegion                        Field = "region"
+	FieldParish                        Field = "parish"
+	FieldLSOA                          Field = "lsoa"
+	FieldMSOA                          Field = "msoa"
+	FieldLSOA11                        Field = "lsoa11"
+	FieldMSOA11                        Field = "msoa11"
+	FieldLSOA21                        Field = "lsoa21"
+	FieldMSOA21                        Field = "msoa21"
+	FieldCED                           Field = "ced"
+	FieldCCG                           Field = "ccg"
+	FieldNUTS                          Field = "nuts"
+	FieldPFA                           Field = "pfa"
+	FieldNHSRegion                     Field = "nhs_region"
+	FieldDateOfIntroduction            Field = "date_of_introduction"
+	FieldCodes                         Field = "codes"
+)
+
+// fields is the set of known filter fields.
+var fields = map[Field]bool{
+	FieldPostcode:                      true,
+	FieldOutcode:                       true,
+	FieldIncode:                        true,
+	FieldQuality:                       true,
+	FieldEastings:                      true,
+	FieldNorthings:                     true,
+	FieldCountry:                       true,
+	FieldNHSHA:                         true,
+	FieldAdminCounty:                   true,
+	FieldAdminDistrict:                 true,
+	FieldAdminWard:                     true,
+	FieldLongitude:                     true,
+	FieldLatitude:                      true,
+	FieldParliamentaryConstituency:     true,
+	FieldParliamentaryConstituency2024: true,
+	FieldEuropeanElectoralRegion:       true,
+	FieldPrimaryCareTrust:              true,
+	FieldRegion:                        true,
+	FieldParish:                        true,
+	FieldLSOA:                          true,
+	FieldMSOA:                          true,
+	FieldLSOA11:                        true,
+	FieldMSOA11:                        true,
+	FieldLSOA21:                        true,
+	FieldMSOA21:                        true,
+	FieldCED:                           true,
+	FieldCCG:                           true,
+	FieldNUTS:                          true,
+	FieldPFA:                           true,
+	FieldNHSRegion:                     true,
+	FieldDateOfIntroduction:            true,
+	FieldCodes:                         true,
+}
+
+// Valid reports whether f is a known Postcode attribute.
+func (f Field) Valid() bool {
+	return fields[f]
+}
+
+// joinFields returns the fields as a comma separated list, as expected by the filter query parameter.
+func joinFields(filters []Field) string {
+	s := make([]string, len(filters))
+	for i, f := range filters {
+		s[i] = string(f)
+	}
+
+	return strings.Join(s, ",")
+}
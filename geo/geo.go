@@ -0,0 +1,93 @@
+// Package geo provides geodesy utilities for the coordinates returned by postcodes.io: conversion between the
+// British National Grid (OSGB36) and WGS84, great-circle distances and bearings, polygons, geohashes
+// and hexagonal grids.
+package geo
+
+import "math"
+
+// EarthRadius is the mean radius of the Earth in metres, used for great-circle computations.
+const EarthRadius = 6371008.8
+
+// Point is a WGS84 coordinate in decimal degrees.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// BoundingBox is a WGS84 bounding box, with Min the south-west corner and Max the north-east corner.
+type BoundingBox struct {
+	Min Point
+	Max Point
+}
+
+// Contains reports whether p is inside the bounding box, boundaries included.
+func (b BoundingBox) Contains(p Point) bool {
+	return p.Latitude >= b.Min.Latitude && p.Latitude <= b.Max.Latitude &&
+		p.Longitude >= b.Min.Longitude && p.Longitude <= b.Max.Longitude
+}
+
+// Overlaps reports whether the bounding boxes overlap, boundaries included.
+func (b BoundingBox) Overlaps(o BoundingBox) bool {
+	return b.Min.Latitude <= o.Max.Latitude && o.Min.Latitude <= b.Max.Latitude &&
+		b.Min.Longitude <= o.Max.Longitude && o.Min.Longitude <= b.Max.Longitude
+}
+
+// Center returns the centre of the bounding box.
+func (b BoundingBox) Center() Point {
+	return Point{
+		Latitude:  (b.Min.Latitude + b.Max.Latitude) / 2,
+		Longitude: (b.Min.Longitude + b.Max.Longitude) / 2,
+	}
+}
+
+// Distance returns the great-circle distance in metres between a and b, using the haversine formula.
+func Distance(a, b Point) float64 {
+	return DistanceWithRadius(a, b, EarthRadius)
+}
+
+// DistanceWithRadius returns the great-circle distance between a and b on a sphere of the given radius, in the unit
+// of the radius.
+func DistanceWithRadius(a, b Point, radius float64) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * radius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Bearing returns the initial great-circle bearing from a to b, in degrees clockwise from north in [0, 360).
+func Bearing(a, b Point) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLon := radians(b.Longitude - a.Longitude)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the point reached travelling distance metres from p along a great circle with the given
+// initial bearing in degrees.
+func Destination(p Point, bearing, distance float64) Point {
+	lat1, lon1 := radians(p.Latitude), radians(p.Longitude)
+	theta := radians(bearing)
+	delta := distance / EarthRadius
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+
+	return Point{
+		Latitude:  degrees(lat2),
+		Longitude: math.Mod(degrees(lon2)+540, 360) - 180,
+	}
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
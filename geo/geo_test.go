@@ -0,0 +1,177 @@
+package geo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// nw16xe is the postcode NW1 6XE, whose eastings and northings are 527850 and 182134.
+var nw16xe = geo.Point{Latitude: 51.523659, Longitude: -0.158541}
+
+func TestBNGToWGS84(t *testing.T) {
+	tests := []struct {
+		name      string
+		eastings  float64
+		northings float64
+		expected  geo.Point
+	}{
+		{
+			name:      "NW1 6XE",
+			eastings:  527850,
+			northings: 182134,
+			expected:  nw16xe,
+		},
+		{
+			name:      "SW1A 2AA",
+			eastings:  530047,
+			northings: 179951,
+			expected:  geo.Point{Latitude: 51.50354, Longitude: -0.127695},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := geo.BNGToWGS84(test.eastings, test.northings)
+
+			assert.InDelta(t, test.expected.Latitude, p.Latitude, 0.0001)
+			assert.InDelta(t, test.expected.Longitude, p.Longitude, 0.0001)
+		})
+	}
+}
+
+func TestWGS84ToBNG(t *testing.T) {
+	e, n := geo.WGS84ToBNG(nw16xe)
+
+	assert.InDelta(t, 527850, e, 5)
+	assert.InDelta(t, 182134, n, 5)
+}
+
+func TestBNG_RoundTrip(t *testing.T) {
+	for _, grid := range [][2]float64{{527850, 182134}, {100000, 900000}, {650000, 300000}, {250000, 50000}} {
+		e, n := geo.WGS84ToBNG(geo.BNGToWGS84(grid[0], grid[1]))
+
+		assert.InDelta(t, grid[0], e, 0.01)
+		assert.InDelta(t, grid[1], n, 0.01)
+	}
+}
+
+func TestBNGToWGS84_NonFinite(t *testing.T) {
+	for _, grid := range [][2]float64{{100, math.NaN()}, {100, math.Inf(1)}, {math.NaN(), math.NaN()}} {
+		p := geo.BNGToWGS84(grid[0], grid[1])
+
+		assert.True(t, math.IsNaN(p.Latitude))
+		assert.True(t, math.IsNaN(p.Longitude))
+	}
+
+	grid := geo.HexGrid{Size: math.NaN()}
+	assert.True(t, math.IsNaN(grid.Center(geo.HexCell{Q: 1}).Latitude))
+	assert.True(t, math.IsNaN(grid.Polygon(geo.HexCell{Q: 1})[0][0].Latitude))
+}
+
+func TestDistance(t *testing.T) {
+	oneDegree := geo.EarthRadius * math.Pi / 180
+
+	assert.InDelta(t, oneDegree, geo.Distance(geo.Point{}, geo.Point{Latitude: 1}), 0.001)
+	assert.InDelta(t, oneDegree, geo.Distance(geo.Point{}, geo.Point{Longitude: 1}), 0.001)
+	assert.Equal(t, 0.0, geo.Distance(nw16xe, nw16xe))
+	assert.InDelta(t, geo.Distance(nw16xe, geo.Point{Latitude: 1}), geo.Distance(geo.Point{Latitude: 1}, nw16xe), 1e-6)
+}
+
+func TestBearing(t *testing.T) {
+	assert.InDelta(t, 0, geo.Bearing(geo.Point{}, geo.Point{Latitude: 1}), 1e-9)
+	assert.InDelta(t, 90, geo.Bearing(geo.Point{}, geo.Point{Longitude: 1}), 1e-9)
+	assert.InDelta(t, 180, geo.Bearing(geo.Point{Latitude: 1}, geo.Point{}), 1e-9)
+	assert.InDelta(t, 270, geo.Bearing(geo.Point{}, geo.Point{Longitude: -1}), 1e-9)
+}
+
+func TestDestination(t *testing.T) {
+	for _, bearing := range []float64{0, 45, 90, 180, 300} {
+		p := geo.Destination(nw16xe, bearing, 1500)
+
+		assert.InDelta(t, 1500, geo.Distance(nw16xe, p), 0.001)
+		assert.InDelta(t, 0, math.Mod(geo.Bearing(nw16xe, p)-bearing+540, 360)-180, 0.01)
+	}
+}
+
+func TestGeodesicDistance(t *testing.T) {
+	flindersPeak := geo.Point{Latitude: -37.95103342, Longitude: 144.42486789}
+	buninyong := geo.Point{Latitude: -37.65282114, Longitude: 143.92649554}
+
+	assert.InDelta(t, 54972.271, geo.GeodesicDistance(flindersPeak, buninyong), 0.001)
+	assert.Equal(t, 0.0, geo.GeodesicDistance(nw16xe, nw16xe))
+	antipode := geo.Point{Longitude: 180}
+	assert.InDelta(t, geo.Distance(geo.Point{}, antipode), geo.GeodesicDistance(geo.Point{}, antipode), 1e-6)
+}
+
+func TestPolygon(t *testing.T) {
+	box := func(minLat, minLon, maxLat, maxLon float64) geo.BoundingBox {
+		return geo.BoundingBox{Min: geo.Point{Latitude: minLat, Longitude: minLon}, Max: geo.Point{Latitude: maxLat, Longitude: maxLon}}
+	}
+	ring := func(b geo.BoundingBox) []geo.Point {
+		return []geo.Point{
+			b.Min, {Latitude: b.Min.Latitude, Longitude: b.Max.Longitude}, b.Max, {Latitude: b.Max.Latitude, Longitude: b.Min.Longitude},
+		}
+	}
+	polygon := geo.Polygon{ring(box(51, -1, 53, 1)), ring(box(51.5, -0.5, 52.5, 0.5))}
+
+	assert.Equal(t, box(51, -1, 53, 1), polygon.BoundingBox())
+
+	assert.True(t, polygon.Contains(geo.Point{Latitude: 51.2, Longitude: 0}))
+	assert.True(t, polygon.Contains(geo.Point{Latitude: 52, Longitude: 0.9}))
+	assert.False(t, polygon.Contains(geo.Point{Latitude: 52, Longitude: 0}), "inside the hole")
+	assert.False(t, polygon.Contains(geo.Point{Latitude: 54, Longitude: 0}))
+
+	assert.True(t, polygon.Intersects(box(51.1, -0.9, 51.2, -0.8)), "inside")
+	assert.True(t, polygon.Intersects(box(50, -2, 54, 2)), "containing the polygon")
+	assert.True(t, polygon.Intersects(box(52.9, 0.9, 54, 2)), "across a corner")
+	assert.True(t, polygon.Intersects(box(51.9, -0.1, 52.1, 0.6)), "across the hole edge")
+	assert.False(t, polygon.Intersects(box(51.9, -0.1, 52.1, 0.1)), "inside the hole")
+	assert.False(t, polygon.Intersects(box(54, -1, 55, 1)), "outside")
+
+	triangle := geo.Polygon{{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 2}, {Latitude: 2, Longitude: 0}}}
+	assert.False(t, triangle.Intersects(box(1.5, 1.5, 2, 2)), "outside the hypotenuse, inside the bounding box")
+	assert.True(t, triangle.Intersects(box(0.9, 0.9, 2, 2)), "across the hypotenuse")
+}
+
+func TestGeohash(t *testing.T) {
+	assert.Equal(t, "u4pruydqqvj", geo.Geohash(geo.Point{Latitude: 57.64911, Longitude: 10.40744}, 11))
+	assert.Equal(t, "gcpvh", geo.Geohash(nw16xe, 5))
+	assert.Equal(t, "g", geo.Geohash(nw16xe, 0))
+	assert.Len(t, geo.Geohash(nw16xe, 20), 12)
+
+	box, err := geo.DecodeGeohash("GCPVH7")
+	assert.NoError(t, err)
+	assert.True(t, box.Contains(nw16xe))
+	assert.Equal(t, "gcpvh7", geo.Geohash(box.Center(), 6))
+
+	_, err = geo.DecodeGeohash("gcpa")
+	assert.ErrorIs(t, err, geo.ErrInvalidGeohash)
+
+	_, err = geo.DecodeGeohash("")
+	assert.ErrorIs(t, err, geo.ErrInvalidGeohash)
+}
+
+func TestHexGrid(t *testing.T) {
+	grid := geo.HexGrid{Size: 500}
+
+	cell := grid.Cell(nw16xe)
+	assert.Equal(t, cell, grid.Cell(grid.Center(cell)))
+	assert.LessOrEqual(t, geo.Distance(nw16xe, grid.Center(cell)), 500.0)
+
+	hexagon := grid.Polygon(cell)
+	assert.Len(t, hexagon[0], 6)
+	assert.True(t, hexagon.Contains(grid.Center(cell)))
+
+	for _, vertex := range hexagon[0] {
+		assert.InEpsilon(t, 500, geo.Distance(grid.Center(cell), vertex), 0.005)
+	}
+
+	for _, neighbour := range []geo.HexCell{{Q: 1}, {R: 1}, {Q: -1, R: 1}, {Q: -1}, {R: -1}, {Q: 1, R: -1}} {
+		other := geo.HexCell{Q: cell.Q + neighbour.Q, R: cell.R + neighbour.R}
+		assert.Equal(t, other, grid.Cell(grid.Center(other)))
+		assert.InEpsilon(t, 500*math.Sqrt(3), geo.Distance(grid.Center(cell), grid.Center(other)), 0.005)
+	}
+}
@@ -0,0 +1,64 @@
+package geo
+
+import "math"
+
+const (
+	wgs84Flattening   = 1 / 298.257223563
+	vincentyTolerance = 1e-12
+	vincentyMaxIter   = 200
+)
+
+// GeodesicDistance returns the distance in metres between a and b on the WGS84 ellipsoid, using Vincenty's inverse
+// formula. This is the distance PostGIS computes between geography points, and so the distance returned by the
+// postcodes.io reverse geocoding endpoints. It falls back to Distance for nearly antipodal points, where the formula
+// does not converge.
+func GeodesicDistance(a, b Point) float64 {
+	const f = wgs84Flattening
+
+	major, minor := wgs84.a, wgs84.b
+
+	l := radians(b.Longitude - a.Longitude)
+	u1 := math.Atan((1 - f) * math.Tan(radians(a.Latitude)))
+	u2 := math.Atan((1 - f) * math.Tan(radians(b.Latitude)))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := l
+
+	for i := 0; i < vincentyMaxIter; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+
+		if sinSigma == 0 {
+			return 0
+		}
+
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha := 1 - sinAlpha*sinAlpha
+
+		cos2SigmaM := 0.0
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+
+		c := f / 16 * cos2Alpha * (4 + f*(4-3*cos2Alpha))
+		prev := lambda
+		lambda = l + (1-c)*f*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+		if math.Abs(lambda-prev) > vincentyTolerance {
+			continue
+		}
+
+		uSq := cos2Alpha * (major*major - minor*minor) / (minor * minor)
+		k1 := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+		k2 := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+		deltaSigma := k2 * sinSigma * (cos2SigmaM + k2/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+			k2/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+		return minor * k1 * (sigma - deltaSigma)
+	}
+
+	return Distance(a, b)
+}
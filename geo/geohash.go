@@ -0,0 +1,91 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// geohashAlphabet is the base 32 alphabet of geohashes.
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+const (
+	geohashBits         = 5
+	maxGeohashPrecision = 12
+)
+
+// ErrInvalidGeohash is returned when decoding a string that is not a geohash.
+var ErrInvalidGeohash = errors.New("geo: invalid geohash")
+
+// Geohash returns the geohash of p with the given number of characters, from 1 to 12. A precision of 6 gives cells of
+// about 1.2 km by 0.6 km, and 7 of about 150 m by 150 m.
+func Geohash(p Point, precision int) string {
+	precision = max(1, min(precision, maxGeohashPrecision))
+	lat, lon := [2]float64{-90, 90}, [2]float64{-180, 180}
+
+	var b strings.Builder
+
+	even := true
+	bits, ch := 0, 0
+
+	for b.Len() < precision {
+		interval, value := &lat, p.Latitude
+		if even {
+			interval, value = &lon, p.Longitude
+		}
+
+		mid := (interval[0] + interval[1]) / 2
+		ch <<= 1
+
+		if value >= mid {
+			ch |= 1
+			interval[0] = mid
+		} else {
+			interval[1] = mid
+		}
+
+		even = !even
+
+		if bits++; bits == geohashBits {
+			b.WriteByte(geohashAlphabet[ch])
+			bits, ch = 0, 0
+		}
+	}
+
+	return b.String()
+}
+
+// DecodeGeohash returns the cell of a geohash, case insensitive.
+func DecodeGeohash(hash string) (BoundingBox, error) {
+	if hash == "" {
+		return BoundingBox{}, fmt.Errorf("%w: empty", ErrInvalidGeohash)
+	}
+
+	lat, lon := [2]float64{-90, 90}, [2]float64{-180, 180}
+	even := true
+
+	for _, r := range strings.ToLower(hash) {
+		ch := strings.IndexRune(geohashAlphabet, r)
+		if ch < 0 {
+			return BoundingBox{}, fmt.Errorf("%w: %q", ErrInvalidGeohash, hash)
+		}
+
+		for bit := geohashBits - 1; bit >= 0; bit-- {
+			interval := &lat
+			if even {
+				interval = &lon
+			}
+
+			mid := (interval[0] + interval[1]) / 2
+			if ch&(1<<bit) != 0 {
+				interval[0] = mid
+			} else {
+				interval[1] = mid
+			}
+
+			even = !even
+		}
+	}
+
+	return BoundingBox{Min: Point{Latitude: lat[0], Longitude: lon[0]}, Max: Point{Latitude: lat[1], Longitude: lon[1]}}, nil
+}
@@ -0,0 +1,73 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// HexGrid is a grid of regular hexagons on the British National Grid, with the given circumradius in metres.
+// Hexagons are pointy-topped, and the cell (0, 0) is centred on the grid origin. Being defined in metres, cells have
+// the same area everywhere in Great Britain, which suits heatmaps better than latitude and longitude cells.
+type HexGrid struct {
+	Size float64
+}
+
+// HexCell is a cell of a HexGrid, in axial coordinates.
+type HexCell struct {
+	Q int
+	R int
+}
+
+// String returns the cell as "q,r".
+func (c HexCell) String() string {
+	return fmt.Sprintf("%d,%d", c.Q, c.R)
+}
+
+// Cell returns the cell containing p.
+func (g HexGrid) Cell(p Point) HexCell {
+	x, y := WGS84ToBNG(p)
+
+	q := (math.Sqrt(3)/3*x - y/3) / g.Size //nolint: gomnd
+	r := (2.0 / 3 * y) / g.Size            //nolint: gomnd
+
+	return roundHex(q, r)
+}
+
+// Center returns the centre of the cell.
+func (g HexGrid) Center(c HexCell) Point {
+	return BNGToWGS84(g.center(c))
+}
+
+// Polygon returns the hexagon of the cell.
+func (g HexGrid) Polygon(c HexCell) Polygon {
+	x, y := g.center(c)
+	ring := make([]Point, 6) //nolint: gomnd
+
+	for i := range ring {
+		angle := math.Pi / 6 * float64(2*i+1) //nolint: gomnd
+		ring[i] = BNGToWGS84(x+g.Size*math.Cos(angle), y+g.Size*math.Sin(angle))
+	}
+
+	return Polygon{ring}
+}
+
+// center returns the eastings and northings of the centre of the cell.
+func (g HexGrid) center(c HexCell) (float64, float64) {
+	return g.Size * math.Sqrt(3) * (float64(c.Q) + float64(c.R)/2), g.Size * 3 / 2 * float64(c.R) //nolint: gomnd
+}
+
+// roundHex returns the cell containing the fractional axial coordinates, rounding them in cube coordinates.
+func roundHex(q, r float64) HexCell {
+	s := -q - r
+	rq, rr, rs := math.Round(q), math.Round(r), math.Round(s)
+	dq, dr, ds := math.Abs(rq-q), math.Abs(rr-r), math.Abs(rs-s)
+
+	switch {
+	case dq > dr && dq > ds:
+		rq = -rr - rs
+	case dr > ds:
+		rr = -rq - rs
+	}
+
+	return HexCell{Q: int(rq), R: int(rr)}
+}
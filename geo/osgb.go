@@ -0,0 +1,205 @@
+package geo
+
+import "math"
+
+// ellipsoid is a reference ellipsoid, defined by its semi-major and semi-minor axes in metres.
+type ellipsoid struct {
+	a, b float64
+}
+
+// eccentricitySquared returns the first eccentricity squared of the ellipsoid.
+func (e ellipsoid) eccentricitySquared() float64 {
+	return 1 - (e.b*e.b)/(e.a*e.a)
+}
+
+var (
+	airy1830 = ellipsoid{a: 6377563.396, b: 6356256.909}
+	wgs84    = ellipsoid{a: 6378137, b: 6356752.314245}
+)
+
+// National Grid Transverse Mercator projection parameters.
+const (
+	scaleFactor     = 0.9996012717
+	trueOriginLat   = 49
+	trueOriginLon   = -2
+	falseEastings   = 400000
+	falseNorthings  = -100000
+	meridianEpsilon = 0.00001
+	meridianMaxIter = 100
+)
+
+// helmert holds the parameters of a 7-parameter Helmert transformation: translations in metres, scale in ppm and
+// rotations in arc seconds.
+type helmert struct {
+	tx, ty, tz float64
+	s          float64
+	rx, ry, rz float64
+}
+
+var (
+	osgb36ToWGS84 = helmert{tx: 446.448, ty: -125.157, tz: 542.060, s: -20.4894, rx: 0.1502, ry: 0.2470, rz: 0.8421}
+	wgs84ToOSGB36 = helmert{tx: -446.448, ty: 125.157, tz: -542.060, s: 20.4894, rx: -0.1502, ry: -0.2470, rz: -0.8421}
+)
+
+// BNGToWGS84 converts British National Grid eastings and northings (OSGB36) to a WGS84 coordinate.
+// It uses a Helmert transformation, which is accurate to around 5 metres. Non-finite input gives a NaN point.
+func BNGToWGS84(eastings, northings float64) Point {
+	lat, lon := fromGrid(eastings, northings)
+	x, y, z := toCartesian(lat, lon, airy1830)
+	x, y, z = osgb36ToWGS84.apply(x, y, z)
+	lat, lon = fromCartesian(x, y, z, wgs84)
+
+	return Point{Latitude: degrees(lat), Longitude: degrees(lon)}
+}
+
+// WGS84ToBNG converts a WGS84 coordinate to British National Grid eastings and northings (OSGB36).
+// It uses a Helmert transformation, which is accurate to around 5 metres.
+func WGS84ToBNG(p Point) (eastings, northings float64) {
+	x, y, z := toCartesian(radians(p.Latitude), radians(p.Longitude), wgs84)
+	x, y, z = wgs84ToOSGB36.apply(x, y, z)
+	lat, lon := fromCartesian(x, y, z, airy1830)
+
+	return toGrid(lat, lon)
+}
+
+// meridionalArc returns the developed arc of a meridian from the true origin to latitude lat, for the Airy 1830
+// ellipsoid scaled by the National Grid scale factor.
+func meridionalArc(lat float64) float64 {
+	a, b := airy1830.a, airy1830.b
+	n := (a - b) / (a + b)
+	n2, n3 := n*n, n*n*n
+	lat0 := radians(trueOriginLat)
+	dLat, sLat := lat-lat0, lat+lat0
+
+	return b * scaleFactor * ((1+n+5.0/4*n2+5.0/4*n3)*dLat -
+		(3*n+3*n2+21.0/8*n3)*math.Sin(dLat)*math.Cos(sLat) +
+		(15.0/8*n2+15.0/8*n3)*math.Sin(2*dLat)*math.Cos(2*sLat) -
+		35.0/24*n3*math.Sin(3*dLat)*math.Cos(3*sLat))
+}
+
+// radiiOfCurvature returns the transverse (nu) and meridional (rho) radii of curvature at latitude lat, scaled by the
+// National Grid scale factor.
+func radiiOfCurvature(lat float64) (nu, rho float64) {
+	e2 := airy1830.eccentricitySquared()
+	sin2 := math.Sin(lat) * math.Sin(lat)
+	nu = airy1830.a * scaleFactor / math.Sqrt(1-e2*sin2)
+	rho = airy1830.a * scaleFactor * (1 - e2) / math.Pow(1-e2*sin2, 1.5)
+
+	return nu, rho
+}
+
+// toGrid projects an OSGB36 latitude and longitude in radians to National Grid eastings and northings.
+func toGrid(lat, lon float64) (eastings, northings float64) {
+	nu, rho := radiiOfCurvature(lat)
+	eta2 := nu/rho - 1
+
+	sinLat, cosLat := math.Sin(lat), math.Cos(lat)
+	cos3, cos5 := cosLat*cosLat*cosLat, math.Pow(cosLat, 5)
+	tan2 := math.Tan(lat) * math.Tan(lat)
+	tan4 := tan2 * tan2
+
+	i := meridionalArc(lat) + falseNorthings
+	ii := nu / 2 * sinLat * cosLat
+	iii := nu / 24 * sinLat * cos3 * (5 - tan2 + 9*eta2)
+	iiia := nu / 720 * sinLat * cos5 * (61 - 58*tan2 + tan4)
+	iv := nu * cosLat
+	v := nu / 6 * cos3 * (nu/rho - tan2)
+	vi := nu / 120 * cos5 * (5 - 18*tan2 + tan4 + 14*eta2 - 58*tan2*eta2)
+
+	dLon := lon - radians(trueOriginLon)
+	dLon2 := dLon * dLon
+
+	northings = i + ii*dLon2 + iii*dLon2*dLon2 + iiia*math.Pow(dLon, 6)
+	eastings = falseEastings + iv*dLon + v*dLon2*dLon + vi*math.Pow(dLon, 5)
+
+	return eastings, northings
+}
+
+// fromGrid converts National Grid eastings and northings to an OSGB36 latitude and longitude in radians. It returns
+// NaN if the meridional arc does not converge, as for non-finite northings.
+func fromGrid(eastings, northings float64) (lat, lon float64) {
+	lat = radians(trueOriginLat)
+	m := 0.0
+
+	for i := 0; ; i++ {
+		if i == meridianMaxIter {
+			return math.NaN(), math.NaN()
+		}
+
+		lat += (northings - falseNorthings - m) / (airy1830.a * scaleFactor)
+		m = meridionalArc(lat)
+
+		if math.Abs(northings-falseNorthings-m) < meridianEpsilon {
+			break
+		}
+	}
+
+	nu, rho := radiiOfCurvature(lat)
+	eta2 := nu/rho - 1
+
+	tanLat := math.Tan(lat)
+	tan2 := tanLat * tanLat
+	tan4 := tan2 * tan2
+	secLat := 1 / math.Cos(lat)
+	nu3, nu5, nu7 := math.Pow(nu, 3), math.Pow(nu, 5), math.Pow(nu, 7)
+
+	vii := tanLat / (2 * rho * nu)
+	viii := tanLat / (24 * rho * nu3) * (5 + 3*tan2 + eta2 - 9*tan2*eta2)
+	ix := tanLat / (720 * rho * nu5) * (61 + 90*tan2 + 45*tan4)
+	x := secLat / nu
+	xi := secLat / (6 * nu3) * (nu/rho + 2*tan2)
+	xii := secLat / (120 * nu5) * (5 + 28*tan2 + 24*tan4)
+	xiia := secLat / (5040 * nu7) * (61 + 662*tan2 + 1320*tan4 + 720*tan4*tan2)
+
+	dE := eastings - falseEastings
+	dE2 := dE * dE
+
+	lat = lat - vii*dE2 + viii*dE2*dE2 - ix*math.Pow(dE, 6)
+	lon = radians(trueOriginLon) + x*dE - xi*dE2*dE + xii*math.Pow(dE, 5) - xiia*math.Pow(dE, 7)
+
+	return lat, lon
+}
+
+// toCartesian converts a geodetic latitude and longitude in radians, at zero height, to cartesian coordinates.
+func toCartesian(lat, lon float64, e ellipsoid) (x, y, z float64) {
+	e2 := e.eccentricitySquared()
+	sinLat := math.Sin(lat)
+	nu := e.a / math.Sqrt(1-e2*sinLat*sinLat)
+
+	x = nu * math.Cos(lat) * math.Cos(lon)
+	y = nu * math.Cos(lat) * math.Sin(lon)
+	z = (1 - e2) * nu * sinLat
+
+	return x, y, z
+}
+
+// fromCartesian converts cartesian coordinates to a geodetic latitude and longitude in radians.
+func fromCartesian(x, y, z float64, e ellipsoid) (lat, lon float64) {
+	const iterations = 10
+
+	e2 := e.eccentricitySquared()
+	p := math.Hypot(x, y)
+	lat = math.Atan2(z, p*(1-e2))
+
+	for i := 0; i < iterations; i++ {
+		sinLat := math.Sin(lat)
+		nu := e.a / math.Sqrt(1-e2*sinLat*sinLat)
+		lat = math.Atan2(z+e2*nu*sinLat, p)
+	}
+
+	return lat, math.Atan2(y, x)
+}
+
+// apply applies the Helmert transformation to cartesian coordinates.
+func (h helmert) apply(x, y, z float64) (x2, y2, z2 float64) {
+	const arcSecond = math.Pi / (180 * 3600)
+
+	s := 1 + h.s*1e-6
+	rx, ry, rz := h.rx*arcSecond, h.ry*arcSecond, h.rz*arcSecond
+
+	x2 = h.tx + s*x - rz*y + ry*z
+	y2 = h.ty + rz*x + s*y - rx*z
+	z2 = h.tz - ry*x + rx*y + s*z
+
+	return x2, y2, z2
+}
@@ -0,0 +1,87 @@
+package geo
+
+import "math"
+
+// Polygon is a WGS84 polygon given, as in GeoJSON, by its exterior ring followed by the rings of its holes, if any.
+// Rings may be closed or not. Edges are straight lines in latitude and longitude, which is accurate for polygons
+// the size of UK administrative areas.
+type Polygon [][]Point
+
+// Contains reports whether p is inside the polygon and outside its holes.
+func (pg Polygon) Contains(p Point) bool {
+	inside := false
+
+	for _, ring := range pg {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			a, b := ring[i], ring[j]
+			if (a.Latitude > p.Latitude) != (b.Latitude > p.Latitude) &&
+				p.Longitude < (b.Longitude-a.Longitude)*(p.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+				inside = !inside
+			}
+		}
+	}
+
+	return inside
+}
+
+// BoundingBox returns the bounding box of the exterior ring of the polygon.
+func (pg Polygon) BoundingBox() BoundingBox {
+	if len(pg) == 0 || len(pg[0]) == 0 {
+		return BoundingBox{}
+	}
+
+	b := BoundingBox{Min: pg[0][0], Max: pg[0][0]}
+
+	for _, p := range pg[0][1:] {
+		b.Min.Latitude = math.Min(b.Min.Latitude, p.Latitude)
+		b.Min.Longitude = math.Min(b.Min.Longitude, p.Longitude)
+		b.Max.Latitude = math.Max(b.Max.Latitude, p.Latitude)
+		b.Max.Longitude = math.Max(b.Max.Longitude, p.Longitude)
+	}
+
+	return b
+}
+
+// Intersects reports whether the polygon and the bounding box overlap.
+func (pg Polygon) Intersects(b BoundingBox) bool {
+	if !pg.BoundingBox().Overlaps(b) {
+		return false
+	}
+
+	if pg.Contains(b.Center()) {
+		return true
+	}
+
+	for _, ring := range pg {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			if segmentIntersectsBox(ring[j], ring[i], b) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// segmentIntersectsBox reports whether the segment from a to b crosses or touches the bounding box, clipping the
+// segment to the box (Liang-Barsky).
+func segmentIntersectsBox(a, b Point, box BoundingBox) bool {
+	t0, t1 := 0.0, 1.0
+	dx, dy := b.Longitude-a.Longitude, b.Latitude-a.Latitude
+
+	clip := func(p, q float64) bool {
+		switch {
+		case p == 0:
+			return q >= 0
+		case p < 0:
+			t0 = math.Max(t0, q/p)
+		default:
+			t1 = math.Min(t1, q/p)
+		}
+
+		return t0 <= t1
+	}
+
+	return clip(-dx, a.Longitude-box.Min.Longitude) && clip(dx, box.Max.Longitude-a.Longitude) &&
+		clip(-dy, a.Latitude-box.Min.Latitude) && clip(dy, box.Max.Latitude-a.Latitude)
+}
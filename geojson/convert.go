@@ -0,0 +1,195 @@
+package geojson
+
+import (
+	"encoding/json"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/geo"
+)
+
+// PostcodeFeature converts a postcode into a Feature with a Point geometry.
+// It reports false if the postcode has no coordinates and IncludeUngeocoded is not set.
+func PostcodeFeature(p postcodesio.Postcode, opts ...Option) (Feature, bool, error) {
+	return newConfig(opts).postcodeFeature(p)
+}
+
+// ReversePostcodeFeature converts a reverse geocoded postcode into a Feature with a Point geometry and a distance
+// property.
+// It reports false if the postcode has no coordinates and IncludeUngeocoded is not set.
+func ReversePostcodeFeature(p postcodesio.ReversePostcode, opts ...Option) (Feature, bool, error) {
+	return newConfig(opts).reversePostcodeFeature(p)
+}
+
+// PlaceFeature converts a place into a Feature. The geometry is the polygon of the place bounding box when it is
+// known, and the place centroid otherwise.
+func PlaceFeature(p postcodesio.Place, opts ...Option) (Feature, error) {
+	return newConfig(opts).placeFeature(p)
+}
+
+// FromPostcodes converts postcodes into a FeatureCollection.
+func FromPostcodes(postcodes []postcodesio.Postcode, opts ...Option) (FeatureCollection, error) {
+	cfg := newConfig(opts)
+	fc := newFeatureCollection()
+
+	for _, p := range postcodes {
+		f, ok, err := cfg.postcodeFeature(p)
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		if ok {
+			fc.Features = append(fc.Features, f)
+		}
+	}
+
+	return fc, nil
+}
+
+// FromReversePostcodes converts reverse geocoded postcodes into a FeatureCollection.
+func FromReversePostcodes(postcodes []postcodesio.ReversePostcode, opts ...Option) (FeatureCollection, error) {
+	cfg := newConfig(opts)
+	fc := newFeatureCollection()
+
+	for _, p := range postcodes {
+		f, ok, err := cfg.reversePostcodeFeature(p)
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		if ok {
+			fc.Features = append(fc.Features, f)
+		}
+	}
+
+	return fc, nil
+}
+
+// FromBulkPostcodeLookup converts the results of a bulk postcode lookup into a FeatureCollection. Each feature has a
+// query property with the postcode as queried. Postcodes not found are treated as records without coordinates.
+func FromBulkPostcodeLookup(res *postcodesio.BulkPostcodeLookupResponse, opts ...Option) (FeatureCollection, error) {
+	cfg := newConfig(opts)
+	fc := newFeatureCollection()
+
+	for _, r := range res.Result {
+		f, ok, err := cfg.postcodeFeature(r.Result)
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		query, err := json.Marshal(r.Query)
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		f.Properties["query"] = query
+		fc.Features = append(fc.Features, f)
+	}
+
+	return fc, nil
+}
+
+// FromPlaces converts places into a FeatureCollection.
+func FromPlaces(places []postcodesio.Place, opts ...Option) (FeatureCollection, error) {
+	cfg := newConfig(opts)
+	fc := newFeatureCollection()
+
+	for _, p := range places {
+		f, err := cfg.placeFeature(p)
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		fc.Features = append(fc.Features, f)
+	}
+
+	return fc, nil
+}
+
+func (c *config) postcodeFeature(p postcodesio.Postcode) (Feature, bool, error) {
+	lat, lon, geocoded := p.Location()
+	if !geocoded && !c.includeUngeocoded {
+		return Feature{}, false, nil
+	}
+
+	props, err := c.properties(p)
+	if err != nil {
+		return Feature{}, false, err
+	}
+
+	f := Feature{
+		Type:       TypeFeature,
+		ID:         p.Postcode,
+		Properties: props,
+	}
+
+	if geocoded {
+		f.Geometry = point(lat, lon)
+	}
+
+	return f, true, nil
+}
+
+func (c *config) reversePostcodeFeature(p postcodesio.ReversePostcode) (Feature, bool, error) {
+	f, ok, err := c.postcodeFeature(p.Postcode)
+	if !ok || err != nil {
+		return f, ok, err
+	}
+
+	distance, err := json.Marshal(p.Distance)
+	if err != nil {
+		return Feature{}, false, err
+	}
+
+	f.Properties["distance"] = distance
+
+	return f, true, nil
+}
+
+func (c *config) placeFeature(p postcodesio.Place) (Feature, error) {
+	props, err := c.properties(p)
+	if err != nil {
+		return Feature{}, err
+	}
+
+	f := Feature{
+		Type:       TypeFeature,
+		ID:         p.Code,
+		Properties: props,
+		Geometry:   point(p.Latitude, p.Longitude),
+	}
+
+	if p.MinEastings == 0 && p.MaxEastings == 0 && p.MinNorthings == 0 && p.MaxNorthings == 0 {
+		return f, nil
+	}
+
+	box := p.BoundingBoxWGS84()
+	ring := [][]float64{
+		{box.Min.Longitude, box.Min.Latitude},
+		{box.Max.Longitude, box.Min.Latitude},
+		{box.Max.Longitude, box.Max.Latitude},
+		{box.Min.Longitude, box.Max.Latitude},
+		{box.Min.Longitude, box.Min.Latitude},
+	}
+
+	f.BBox = bbox(box)
+	f.Geometry = &Geometry{Type: TypePolygon, Coordinates: [][][]float64{ring}}
+
+	return f, nil
+}
+
+func newFeatureCollection() FeatureCollection {
+	return FeatureCollection{Type: TypeFeatureCollection, Features: []Feature{}}
+}
+
+func point(lat, lon float64) *Geometry {
+	return &Geometry{Type: TypePoint, Coordinates: []float64{lon, lat}}
+}
+
+// bbox returns the GeoJSON bbox member of a WGS84 bounding box.
+func bbox(box geo.BoundingBox) []float64 {
+	return []float64{box.Min.Longitude, box.Min.Latitude, box.Max.Longitude, box.Max.Latitude}
+}
@@ -0,0 +1,102 @@
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/leandrorondon/postcodesio-go"
+)
+
+// ErrEncoderClosed is returned when writing to an Encoder that has been closed.
+var ErrEncoderClosed = errors.New("geojson: encoder closed")
+
+// Encoder writes a FeatureCollection to a stream one feature at a time, so that large collections are never held in
+// memory. Close must be called to terminate the collection.
+type Encoder struct {
+	w       io.Writer
+	cfg     *config
+	started bool
+	closed  bool
+}
+
+// NewEncoder creates an Encoder writing to w. The options apply to every record encoded.
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	return &Encoder{w: w, cfg: newConfig(opts)}
+}
+
+// Encode writes a feature to the collection.
+func (e *Encoder) Encode(f Feature) error {
+	if e.closed {
+		return ErrEncoderClosed
+	}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	prefix := ","
+	if !e.started {
+		prefix = `{"type":"FeatureCollection","features":[`
+		e.started = true
+	}
+
+	if _, err := io.WriteString(e.w, prefix); err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(b)
+
+	return err
+}
+
+// EncodePostcode writes a postcode to the collection. Postcodes without coordinates are skipped unless
+// IncludeUngeocoded is set.
+func (e *Encoder) EncodePostcode(p postcodesio.Postcode) error {
+	f, ok, err := e.cfg.postcodeFeature(p)
+	if !ok || err != nil {
+		return err
+	}
+
+	return e.Encode(f)
+}
+
+// EncodeReversePostcode writes a reverse geocoded postcode to the collection. Postcodes without coordinates are skipped
+// unless IncludeUngeocoded is set.
+func (e *Encoder) EncodeReversePostcode(p postcodesio.ReversePostcode) error {
+	f, ok, err := e.cfg.reversePostcodeFeature(p)
+	if !ok || err != nil {
+		return err
+	}
+
+	return e.Encode(f)
+}
+
+// EncodePlace writes a place to the collection.
+func (e *Encoder) EncodePlace(p postcodesio.Place) error {
+	f, err := e.cfg.placeFeature(p)
+	if err != nil {
+		return err
+	}
+
+	return e.Encode(f)
+}
+
+// Close terminates the collection. It does not close the underlying writer.
+func (e *Encoder) Close() error {
+	if e.closed {
+		return nil
+	}
+
+	e.closed = true
+
+	end := "]}"
+	if !e.started {
+		end = `{"type":"FeatureCollection","features":[]}`
+	}
+
+	_, err := io.WriteString(e.w, end)
+
+	return err
+}
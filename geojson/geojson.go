@@ -0,0 +1,99 @@
+// Package geojson converts postcodes.io results into GeoJSON (RFC 7946) feature collections.
+package geojson
+
+import (
+	"encoding/json"
+
+	"github.com/leandrorondon/postcodesio-go"
+)
+
+// GeoJSON object types.
+const (
+	TypeFeatureCollection = "FeatureCollection"
+	TypeFeature           = "Feature"
+	TypePoint             = "Point"
+	TypePolygon           = "Polygon"
+)
+
+// FeatureCollection is a GeoJSON FeatureCollection object.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature object.
+// Geometry is nil for records without coordinates, which are only included when IncludeUngeocoded is set.
+type Feature struct {
+	Type       string                     `json:"type"`
+	ID         string                     `json:"id,omitempty"`
+	BBox       []float64                  `json:"bbox,omitempty"`
+	Geometry   *Geometry                  `json:"geometry"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// Geometry is a GeoJSON Point or Polygon geometry object.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// Option describes the type for functional options used when converting results to GeoJSON.
+type Option func(*config)
+
+type config struct {
+	fields            []postcodesio.Field
+	includeUngeocoded bool
+}
+
+// WithFields is the option to restrict the feature properties to the given attributes. By default, every attribute of
+// the record is a property. Place attributes not defined as Field constants can be selected by converting their JSON
+// name, e.g. postcodesio.Field("name_1").
+func WithFields(fields ...postcodesio.Field) Option {
+	return func(c *config) {
+		c.fields = fields
+	}
+}
+
+// IncludeUngeocoded is the option to include records without coordinates as features with a null geometry, instead of
+// skipping them.
+func IncludeUngeocoded() Option {
+	return func(c *config) {
+		c.includeUngeocoded = true
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// properties returns the JSON attributes of v selected by the configuration.
+func (c *config) properties(v interface{}) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+
+	if len(c.fields) == 0 {
+		return all, nil
+	}
+
+	selected := make(map[string]json.RawMessage, len(c.fields))
+
+	for _, f := range c.fields {
+		if value, ok := all[string(f)]; ok {
+			selected[string(f)] = value
+		}
+	}
+
+	return selected, nil
+}
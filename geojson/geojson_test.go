@@ -0,0 +1,193 @@
+package geojson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/geo"
+	"github.com/leandrorondon/postcodesio-go/geojson"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	geocoded = postcodesio.Postcode{
+		Postcode:      "NW1 6XE",
+		Country:       "England",
+		AdminDistrict: postcodesio.Some("Westminster"),
+		Longitude:     postcodesio.Some(-0.158541),
+		Latitude:      postcodesio.Some(51.523659),
+	}
+	ungeocoded = postcodesio.Postcode{
+		Postcode: "GY1 1AA",
+		Country:  "Channel Islands",
+	}
+)
+
+func TestFromPostcodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []geojson.Option
+		expected string
+	}{
+		{
+			name:     "selected fields",
+			opts:     []geojson.Option{geojson.WithFields(postcodesio.FieldPostcode, postcodesio.FieldAdminDistrict)},
+			expected: `{"type":"FeatureCollection","features":[{"type":"Feature","id":"NW1 6XE","geometry":{"type":"Point","coordinates":[-0.158541,51.523659]},"properties":{"admin_district":"Westminster","postcode":"NW1 6XE"}}]}`, //nolint: lll
+		},
+		{
+			name:     "include ungeocoded",
+			opts:     []geojson.Option{geojson.WithFields(postcodesio.FieldPostcode), geojson.IncludeUngeocoded()},
+			expected: `{"type":"FeatureCollection","features":[{"type":"Feature","id":"NW1 6XE","geometry":{"type":"Point","coordinates":[-0.158541,51.523659]},"properties":{"postcode":"NW1 6XE"}},{"type":"Feature","id":"GY1 1AA","geometry":null,"properties":{"postcode":"GY1 1AA"}}]}`, //nolint: lll
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fc, err := geojson.FromPostcodes([]postcodesio.Postcode{geocoded, ungeocoded}, test.opts...)
+			assert.NoError(t, err)
+
+			b, err := json.Marshal(fc)
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(b))
+		})
+	}
+}
+
+func TestFromPostcodes_AllFields(t *testing.T) {
+	fc, err := geojson.FromPostcodes([]postcodesio.Postcode{geocoded})
+
+	assert.NoError(t, err)
+	assert.Len(t, fc.Features, 1)
+	assert.Equal(t, `"England"`, string(fc.Features[0].Properties["country"]))
+	assert.Equal(t, `null`, string(fc.Features[0].Properties["admin_county"]))
+}
+
+func TestFromReversePostcodes(t *testing.T) {
+	fc, err := geojson.FromReversePostcodes(
+		[]postcodesio.ReversePostcode{{Postcode: geocoded, Distance: 16.25}},
+		geojson.WithFields(postcodesio.FieldPostcode),
+	)
+
+	assert.NoError(t, err)
+	assert.Len(t, fc.Features, 1)
+	assert.Equal(t, map[string]json.RawMessage{
+		"postcode": json.RawMessage(`"NW1 6XE"`),
+		"distance": json.RawMessage(`16.25`),
+	}, fc.Features[0].Properties)
+}
+
+func TestFromBulkPostcodeLookup(t *testing.T) {
+	res := &postcodesio.BulkPostcodeLookupResponse{
+		Status: 200,
+		Result: []postcodesio.BulkPostcodeLookupQueryResponse{
+			{Query: "NW16XE", Result: geocoded},
+			{Query: "XX1 1XX"},
+		},
+	}
+
+	fc, err := geojson.FromBulkPostcodeLookup(res, geojson.WithFields(postcodesio.FieldPostcode))
+
+	assert.NoError(t, err)
+	assert.Len(t, fc.Features, 1)
+	assert.Equal(t, `"NW16XE"`, string(fc.Features[0].Properties["query"]))
+}
+
+func TestFromPlaces(t *testing.T) {
+	places := []postcodesio.Place{
+		{
+			Code:         "osgb4000000074564391",
+			Name1:        "Marylebone",
+			Longitude:    -0.158541,
+			Latitude:     51.523659,
+			MinEastings:  527000,
+			MinNorthings: 181000,
+			MaxEastings:  529000,
+			MaxNorthings: 183000,
+		},
+		{
+			Code:      "osgb4000000074559125",
+			Name1:     "Unbounded",
+			Longitude: -0.1,
+			Latitude:  51.5,
+		},
+	}
+
+	fc, err := geojson.FromPlaces(places, geojson.WithFields("name_1"))
+
+	assert.NoError(t, err)
+	assert.Len(t, fc.Features, 2)
+
+	polygon := fc.Features[0]
+	assert.Equal(t, geojson.TypePolygon, polygon.Geometry.Type)
+	assert.Len(t, polygon.BBox, 4)
+	assert.Equal(t, map[string]json.RawMessage{"name_1": json.RawMessage(`"Marylebone"`)}, polygon.Properties)
+
+	ring := polygon.Geometry.Coordinates.([][][]float64)[0]
+	assert.Len(t, ring, 5)
+	assert.Equal(t, ring[0], ring[4])
+
+	assert.Equal(t, geojson.TypePoint, fc.Features[1].Geometry.Type)
+	assert.Nil(t, fc.Features[1].BBox)
+}
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := geojson.NewEncoder(&buf, geojson.WithFields(postcodesio.FieldPostcode))
+	assert.NoError(t, enc.EncodePostcode(geocoded))
+	assert.NoError(t, enc.EncodePostcode(ungeocoded))
+	assert.NoError(t, enc.EncodeReversePostcode(postcodesio.ReversePostcode{Postcode: geocoded, Distance: 1}))
+	assert.NoError(t, enc.Close())
+	assert.ErrorIs(t, enc.EncodePostcode(geocoded), geojson.ErrEncoderClosed)
+
+	var fc geojson.FeatureCollection
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &fc))
+	assert.Equal(t, geojson.TypeFeatureCollection, fc.Type)
+	assert.Len(t, fc.Features, 2)
+
+	expected, err := geojson.FromPostcodes([]postcodesio.Postcode{geocoded}, geojson.WithFields(postcodesio.FieldPostcode))
+	assert.NoError(t, err)
+
+	b, err := json.Marshal(expected.Features[0])
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), string(b))
+}
+
+func TestEncoder_Empty(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc := geojson.NewEncoder(&buf)
+	assert.NoError(t, enc.Close())
+	assert.JSONEq(t, `{"type":"FeatureCollection","features":[]}`, buf.String())
+}
+
+func TestPolygons(t *testing.T) {
+	polygon := geo.Polygon{{{Latitude: 51, Longitude: -1}, {Latitude: 51, Longitude: 1}, {Latitude: 53, Longitude: 0}}}
+
+	g := geojson.PolygonGeometry(polygon)
+	b, err := json.Marshal(g)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"Polygon","coordinates":[[[-1,51],[1,51],[0,53],[-1,51]]]}`, string(b))
+
+	var decoded geojson.Geometry
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+
+	polygons, err := geojson.Polygons(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, []geo.Polygon{{append(polygon[0], polygon[0][0])}}, polygons)
+
+	multi := `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[0,1]]],[[[5,5],[6,5],[5,6]]]]}`
+	assert.NoError(t, json.Unmarshal([]byte(multi), &decoded))
+	polygons, err = geojson.Polygons(decoded)
+	assert.NoError(t, err)
+	assert.Len(t, polygons, 2)
+	assert.True(t, polygons[1].Contains(geo.Point{Latitude: 5.2, Longitude: 5.2}))
+
+	_, err = geojson.Polygons(geojson.Geometry{Type: geojson.TypePoint, Coordinates: []float64{0, 0}})
+	assert.ErrorIs(t, err, geojson.ErrUnsupportedGeometry)
+
+	_, err = geojson.Polygons(geojson.Geometry{Type: geojson.TypePolygon, Coordinates: []float64{0, 0}})
+	assert.ErrorIs(t, err, geojson.ErrUnsupportedGeometry)
+}
@@ -0,0 +1,76 @@
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/leandrorondon/postcodesio-go/geo"
+)
+
+// TypeMultiPolygon is the GeoJSON MultiPolygon geometry type.
+const TypeMultiPolygon = "MultiPolygon"
+
+// ErrUnsupportedGeometry is returned when converting a geometry that is not a Polygon or a MultiPolygon.
+var ErrUnsupportedGeometry = errors.New("geojson: unsupported geometry")
+
+// PolygonGeometry returns the GeoJSON Polygon geometry of a polygon, closing its rings.
+func PolygonGeometry(p geo.Polygon) *Geometry {
+	rings := make([][][]float64, len(p))
+
+	for i, ring := range p {
+		for _, pt := range ring {
+			rings[i] = append(rings[i], []float64{pt.Longitude, pt.Latitude})
+		}
+
+		if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+			rings[i] = append(rings[i], rings[i][0])
+		}
+	}
+
+	return &Geometry{Type: TypePolygon, Coordinates: rings}
+}
+
+// Polygons returns the polygons of a GeoJSON Polygon or MultiPolygon geometry, e.g. to search the postcodes inside
+// them with postcodesio.Client.SearchPolygon.
+func Polygons(g Geometry) ([]geo.Polygon, error) {
+	b, err := json.Marshal(g.Coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	var coordinates [][][][]float64
+
+	switch g.Type {
+	case TypePolygon:
+		var polygon [][][]float64
+		err = json.Unmarshal(b, &polygon)
+		coordinates = [][][][]float64{polygon}
+	case TypeMultiPolygon:
+		err = json.Unmarshal(b, &coordinates)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedGeometry, g.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s coordinates: %w", ErrUnsupportedGeometry, g.Type, err)
+	}
+
+	polygons := make([]geo.Polygon, len(coordinates))
+
+	for i, rings := range coordinates {
+		polygons[i] = make(geo.Polygon, len(rings))
+
+		for j, ring := range rings {
+			for _, position := range ring {
+				if len(position) < 2 { //nolint: gomnd
+					return nil, fmt.Errorf("%w: invalid position %v", ErrUnsupportedGeometry, position)
+				}
+
+				polygons[i][j] = append(polygons[i][j], geo.Point{Latitude: position[1], Longitude: position[0]})
+			}
+		}
+	}
+
+	return polygons, nil
+}
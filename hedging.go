@@ -0,0 +1,229 @@
+package postcodesio
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHedgeDelay  = 100 * time.Millisecond
+	defaultHedgeBudget = 0.1
+	maxHedgeTokens     = 10
+	latencySamples     = 100
+	minLatencySamples  = 20
+)
+
+// HedgePolicy is the configuration of the hedged requests set with WithHedging.
+// Delay is how long to wait for a response before sending a hedge, defaults to 100ms.
+// Percentile, if set (e.g. 95), replaces Delay by that percentile of the observed latencies once enough requests have
+// been observed; Delay is used until then.
+// MaxHedges is the number of hedges sent per request, defaults to 1.
+// Budget is the fraction of requests that may be hedged, defaults to 0.1, so that hedging adds a bounded load to the
+// API and to its rate limits.
+type HedgePolicy struct {
+	Delay      time.Duration
+	Percentile float64
+	MaxHedges  int
+	Budget     float64
+}
+
+// WithHedging is the option to hedge GET requests: if no response is received within the policy delay, an identical
+// request is sent and the first response is used, cancelling the others. A hedged request goes through the circuit
+// breaker as a single request.
+func WithHedging(policy HedgePolicy) ClientOption {
+	return func(c *Client) {
+		c.hedger = newHedger(policy)
+	}
+}
+
+// hedger holds the hedging policy with the state required to apply it.
+type hedger struct {
+	policy HedgePolicy
+
+	mu        sync.Mutex
+	tokens    float64
+	latencies []time.Duration
+	next      int
+}
+
+func newHedger(policy HedgePolicy) *hedger {
+	if policy.Delay <= 0 {
+		policy.Delay = defaultHedgeDelay
+	}
+
+	if policy.MaxHedges <= 0 {
+		policy.MaxHedges = 1
+	}
+
+	if policy.Budget <= 0 {
+		policy.Budget = defaultHedgeBudget
+	}
+
+	return &hedger{policy: policy, tokens: maxHedgeTokens}
+}
+
+// delay returns how long to wait before sending a hedge.
+func (h *hedger) delay() time.Duration {
+	if h.policy.Percentile <= 0 {
+		return h.policy.Delay
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.latencies) < minLatencySamples {
+		return h.policy.Delay
+	}
+
+	sorted := make([]time.Duration, len(h.latencies))
+	copy(sorted, h.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	i := int(float64(len(sorted)-1) * h.policy.Percentile / 100)
+
+	return sorted[i]
+}
+
+// observe records the latency of a successful request.
+func (h *hedger) observe(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.latencies) < latencySamples {
+		h.latencies = append(h.latencies, latency)
+
+		return
+	}
+
+	h.latencies[h.next] = latency
+	h.next = (h.next + 1) % latencySamples
+}
+
+// earn adds the budget of a request to the hedge tokens.
+func (h *hedger) earn() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.tokens += h.policy.Budget
+	if h.tokens > maxHedgeTokens {
+		h.tokens = maxHedgeTokens
+	}
+}
+
+// spend takes a token to send a hedge, reporting false if the budget is exhausted.
+func (h *hedger) spend() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.tokens < 1 {
+		return false
+	}
+
+	h.tokens--
+
+	return true
+}
+
+// attempt is the result of one of the requests of a hedged request.
+type attempt struct {
+	res     *http.Response
+	err     error
+	latency time.Duration
+	id      int
+	cancel  context.CancelFunc
+}
+
+// hedge sends a GET request with failover, hedging it according to the policy.
+// The requests that lose the race are cancelled, and the context of the winning one lasts until its body is closed.
+func (c *Client) hedge(ctx context.Context, path string, opts *requestOptions) (*http.Response, error) {
+	h := c.hedger
+	h.earn()
+
+	results := make(chan attempt, 1+h.policy.MaxHedges)
+	cancels := make([]context.CancelFunc, 0, 1+h.policy.MaxHedges)
+	send := func() {
+		ctx, cancel := context.WithCancel(ctx)
+		id := len(cancels)
+		cancels = append(cancels, cancel)
+
+		go func() {
+			start := time.Now()
+			res, err := c.failover(ctx, http.MethodGet, path, nil, opts)
+			results <- attempt{res: res, err: err, latency: time.Since(start), id: id, cancel: cancel}
+		}()
+	}
+
+	send()
+
+	inFlight, hedges := 1, 0
+	timer := time.NewTimer(h.delay())
+
+	defer timer.Stop()
+
+	var last attempt
+
+	for inFlight > 0 {
+		select {
+		case r := <-results:
+			inFlight--
+
+			if r.err == nil && r.res.StatusCode < http.StatusInternalServerError {
+				h.observe(r.latency)
+				last.discard()
+
+				for id, cancel := range cancels {
+					if id != r.id {
+						cancel()
+					}
+				}
+
+				go discardAttempts(results, inFlight)
+
+				r.res.Body = &cancelBody{ReadCloser: r.res.Body, cancel: r.cancel}
+
+				return r.res, nil
+			}
+
+			last.discard()
+			last = r
+		case <-timer.C:
+			if hedges < h.policy.MaxHedges && h.spend() {
+				send()
+
+				inFlight++
+				hedges++
+
+				timer.Reset(h.delay())
+			}
+		}
+	}
+
+	if last.err != nil {
+		last.cancel()
+
+		return nil, last.err
+	}
+
+	last.res.Body = &cancelBody{ReadCloser: last.res.Body, cancel: last.cancel}
+
+	return last.res, nil
+}
+
+// discard closes the response of the attempt, if any, and cancels its context.
+func (a attempt) discard() {
+	closeBody(a.res)
+
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// discardAttempts waits for the attempts still in flight and discards them.
+func discardAttempts(results <-chan attempt, inFlight int) {
+	for ; inFlight > 0; inFlight-- {
+		(<-results).discard()
+	}
+}
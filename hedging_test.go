@@ -0,0 +1,104 @@
+package postcodesio_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSlowFirstServer returns a server whose first response is delayed until the request is cancelled or the delay
+// elapses, and whose following responses are immediate.
+func newSlowFirstServer(delay time.Duration, calls *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+
+		fmt.Fprintf(w, `{"status":200}`)
+	}))
+}
+
+func TestWithHedging(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := newSlowFirstServer(time.Second, &calls)
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithHedging(postcodesio.HedgePolicy{Delay: 10 * time.Millisecond}))
+
+	start := time.Now()
+	res, err := c.PostcodeLookup(context.Background(), "NW16XE")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.Status)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestWithHedging_DefaultDelay(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := newSlowFirstServer(30*time.Millisecond, &calls)
+	defer srv.Close()
+
+	for _, policy := range []postcodesio.HedgePolicy{{}, {Percentile: 95}} {
+		calls.Store(0)
+
+		c := postcodesio.NewTestClient(srv.URL, postcodesio.WithHedging(policy))
+		_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, calls.Load(), "a response within the default delay must not be hedged")
+	}
+}
+
+func TestWithHedging_Budget(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := newSlowFirstServer(50*time.Millisecond, &calls)
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithHedging(postcodesio.HedgePolicy{Delay: 10 * time.Millisecond, Budget: 0.001}))
+
+	// The initial budget allows a burst of hedges, which must all be spent before hedging stops.
+	for i := 0; i < 10; i++ {
+		calls.Store(0)
+		_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+		assert.NoError(t, err)
+		assert.EqualValues(t, 2, calls.Load())
+	}
+
+	calls.Store(0)
+
+	start := time.Now()
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE")
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.EqualValues(t, 1, calls.Load(), "no hedge must be sent once the budget is exhausted")
+}
+
+func TestWithHedging_PostIsNotHedged(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := newSlowFirstServer(30*time.Millisecond, &calls)
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithHedging(postcodesio.HedgePolicy{Delay: time.Millisecond}))
+
+	_, err := c.BulkPostcodeLookup(context.Background(), postcodesio.BulkPostCodeLookupRequest{Postcodes: []string{"NW16XE"}})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, calls.Load())
+}
@@ -0,0 +1,397 @@
+package postcodesio
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const checkpointHeader = "# postcodesio bulk job checkpoint"
+
+// Sink receives the results of a BulkJob, one chunk at a time.
+// After a restart, Write may be called again for a chunk whose results were written but not checkpointed, and must
+// then replace the previous write, so that the output never holds duplicates.
+type Sink interface {
+	Write(ctx context.Context, offset int, results []BulkPostcodeLookupQueryResponse) error
+}
+
+// Progress reports the progress of a BulkJob, in number of postcodes.
+// ETA is estimated from the throughput of the current run, and is zero until a chunk is completed.
+type Progress struct {
+	Processed int
+	Failed    int
+	Remaining int
+	ETA       time.Duration
+}
+
+// BulkJob looks up a list of postcodes with BulkPostcodeLookup, in chunks, writing the results to a Sink.
+// Completed chunks are recorded to a checkpoint file, so that a job interrupted e.g. by a deploy resumes where it
+// stopped when run again, without querying the completed chunks again.
+// Client, Postcodes, Sink and CheckpointPath are required. ChunkSize defaults to and is capped at 100, Concurrency
+// defaults to 1. OnProgress is optional, and is called after every chunk.
+type BulkJob struct {
+	Client         *Client
+	Postcodes      []string
+	Sink           Sink
+	CheckpointPath string
+	ChunkSize      int
+	Concurrency    int
+	OnProgress     func(Progress)
+	RequestOptions []RequestOption
+}
+
+// Run runs the job until every chunk is completed or ctx is done. Chunks that fail are not checkpointed, and are
+// retried on the next run; Run then returns an error wrapping ErrJobIncomplete.
+func (j *BulkJob) Run(ctx context.Context) error {
+	chunkSize := j.ChunkSize
+	if chunkSize <= 0 || chunkSize > maxBulkSize {
+		chunkSize = maxBulkSize
+	}
+
+	concurrency := j.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	cp, err := openCheckpoint(j.CheckpointPath, chunkSize, j.Postcodes)
+	if err != nil {
+		return err
+	}
+	defer cp.close()
+
+	tracker := newProgressTracker(len(j.Postcodes), j.OnProgress)
+
+	offsets := make(chan int)
+
+	go func() {
+		defer close(offsets)
+
+		for offset := 0; offset < len(j.Postcodes); offset += chunkSize {
+			if cp.done[offset] {
+				tracker.resumed(j.chunk(offset, chunkSize))
+
+				continue
+			}
+
+			select {
+			case offsets <- offset:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		errs   []error
+	)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for offset := range offsets {
+				chunk := j.chunk(offset, chunkSize)
+
+				if err := j.runChunk(ctx, cp, offset, chunk); err != nil {
+					mu.Lock()
+					failed++
+					errs = append(errs, fmt.Errorf("chunk at offset %d: %w", offset, err))
+					mu.Unlock()
+
+					tracker.failed(len(chunk))
+
+					continue
+				}
+
+				tracker.processed(len(chunk))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d chunks failed: %w", ErrJobIncomplete, failed, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// chunk returns the postcodes of the chunk starting at offset.
+func (j *BulkJob) chunk(offset, size int) []string {
+	end := offset + size
+	if end > len(j.Postcodes) {
+		end = len(j.Postcodes)
+	}
+
+	return j.Postcodes[offset:end]
+}
+
+// runChunk looks up a chunk, writes its results to the sink and checkpoints it.
+func (j *BulkJob) runChunk(ctx context.Context, cp *checkpoint, offset int, postcodes []string) error {
+	results := make([]BulkPostcodeLookupQueryResponse, len(postcodes))
+
+	// Blank postcodes are invalid in a bulk lookup. They are not sent, and get an empty result as postcodes not found,
+	// so that results stay aligned with their offsets.
+	var queried []int
+
+	for i, postcode := range postcodes {
+		results[i].Query = postcode
+
+		if strings.TrimSpace(postcode) != "" {
+			queried = append(queried, i)
+		}
+	}
+
+	if len(queried) > 0 {
+		request := BulkPostCodeLookupRequest{Postcodes: make([]string, len(queried))}
+		for j, i := range queried {
+			request.Postcodes[j] = postcodes[i]
+		}
+
+		res, err := j.Client.BulkPostcodeLookup(ctx, request, j.RequestOptions...)
+		if err != nil {
+			return err
+		}
+
+		if res.Status != http.StatusOK {
+			return fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.Status)
+		}
+
+		for k, i := range queried {
+			if k < len(res.Result) {
+				results[i] = res.Result[k]
+			}
+		}
+	}
+
+	if err := j.Sink.Write(ctx, offset, results); err != nil {
+		return err
+	}
+
+	return cp.record(offset)
+}
+
+// checkpoint is an append-only file recording the offsets of the completed chunks of a job.
+// Its first line identifies the chunk size, the number of postcodes and a digest of the postcodes, so that a checkpoint
+// is never applied to a different job.
+type checkpoint struct {
+	mu   sync.Mutex
+	file *os.File
+	done map[int]bool
+}
+
+func openCheckpoint(path string, chunkSize int, postcodes []string) (*checkpoint, error) {
+	header := fmt.Sprintf("%s chunk_size=%d total=%d postcodes=%s",
+		checkpointHeader, chunkSize, len(postcodes), postcodesDigest(postcodes))
+	cp := &checkpoint{done: make(map[int]bool)}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if len(content) > 0 {
+		scanner := bufio.NewScanner(strings.NewReader(string(content)))
+		scanner.Scan()
+
+		if scanner.Text() != header {
+			return nil, fmt.Errorf("%w: %s", ErrCheckpointMismatch, path)
+		}
+
+		for scanner.Scan() {
+			// A line truncated by a crash is ignored; its chunk is processed again.
+			if offset, err := strconv.Atoi(scanner.Text()); err == nil {
+				cp.done[offset] = true
+			}
+		}
+	}
+
+	cp.file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644) //nolint: gomnd
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) == 0 {
+		if err := cp.append(header); err != nil {
+			cp.close()
+
+			return nil, err
+		}
+	} else if !strings.HasSuffix(string(content), "\n") {
+		if err := cp.append(""); err != nil {
+			cp.close()
+
+			return nil, err
+		}
+	}
+
+	return cp, nil
+}
+
+// postcodesDigest returns the SHA-256 of the postcodes, in order.
+func postcodesDigest(postcodes []string) string {
+	h := sha256.New()
+
+	for _, postcode := range postcodes {
+		// Each postcode is NUL terminated, so that e.g. ["AB", "C"] and ["A", "BC"] differ.
+		h.Write([]byte(postcode))
+		h.Write([]byte{0})
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// record records a completed chunk, syncing it to disk.
+func (cp *checkpoint) record(offset int) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return cp.append(strconv.Itoa(offset))
+}
+
+func (cp *checkpoint) append(line string) error {
+	if _, err := cp.file.WriteString(line + "\n"); err != nil {
+		return err
+	}
+
+	return cp.file.Sync()
+}
+
+func (cp *checkpoint) close() {
+	cp.file.Close()
+}
+
+// progressTracker computes and reports the progress of a job.
+type progressTracker struct {
+	mu         sync.Mutex
+	progress   Progress
+	start      time.Time
+	runHandled int
+	onProgress func(Progress)
+}
+
+func newProgressTracker(total int, onProgress func(Progress)) *progressTracker {
+	return &progressTracker{
+		progress:   Progress{Remaining: total},
+		start:      time.Now(),
+		onProgress: onProgress,
+	}
+}
+
+// resumed accounts for a chunk completed by a previous run.
+func (t *progressTracker) resumed(chunk []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.Processed += len(chunk)
+	t.progress.Remaining -= len(chunk)
+}
+
+func (t *progressTracker) processed(n int) {
+	t.update(n, func(p *Progress) { p.Processed += n })
+}
+
+func (t *progressTracker) failed(n int) {
+	t.update(n, func(p *Progress) { p.Failed += n })
+}
+
+// update accounts for n postcodes handled by the current run, and reports the progress.
+func (t *progressTracker) update(n int, fn func(*Progress)) {
+	t.mu.Lock()
+
+	fn(&t.progress)
+	t.progress.Remaining -= n
+	t.runHandled += n
+
+	perPostcode := float64(time.Since(t.start)) / float64(t.runHandled)
+	t.progress.ETA = time.Duration(perPostcode * float64(t.progress.Remaining))
+
+	p := t.progress
+	t.mu.Unlock()
+
+	if t.onProgress != nil {
+		t.onProgress(p)
+	}
+}
+
+// DirSink is a Sink writing the results of each chunk to a JSON file in a directory, named after the chunk offset.
+// Files are written atomically, so that a chunk written again replaces the previous file.
+type DirSink struct {
+	Dir string
+}
+
+// Write implements Sink.
+func (s DirSink) Write(_ context.Context, offset int, results []BulkPostcodeLookupQueryResponse) error {
+	b, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, ".chunk-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	// The chunk is checkpointed once written, so it must reach the disk before, and so must its rename.
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.Dir, fmt.Sprintf("chunk-%09d.json", offset))); err != nil {
+		return err
+	}
+
+	return syncDir(s.Dir)
+}
+
+// syncDir flushes the entries of the directory to disk.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	if err := d.Sync(); err != nil {
+		d.Close()
+
+		return err
+	}
+
+	return d.Close()
+}
@@ -0,0 +1,221 @@
+package postcodesio_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPostcodes(n int) []string {
+	postcodes := make([]string, n)
+	for i := range postcodes {
+		postcodes[i] = fmt.Sprintf("AB%d 1CD", i)
+	}
+
+	return postcodes
+}
+
+func TestBulkJob(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		batchSizes []int
+	)
+
+	srv := newBulkLookupServer(t, &batchSizes, &mu)
+	defer srv.Close()
+
+	dir := t.TempDir()
+
+	var progress []postcodesio.Progress
+
+	job := postcodesio.BulkJob{
+		Client:         postcodesio.NewTestClient(srv.URL),
+		Postcodes:      testPostcodes(250),
+		Sink:           postcodesio.DirSink{Dir: dir},
+		CheckpointPath: filepath.Join(dir, "checkpoint"),
+		OnProgress: func(p postcodesio.Progress) {
+			progress = append(progress, p)
+		},
+	}
+
+	err := job.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{100, 100, 50}, batchSizes)
+
+	assert.Len(t, progress, 3)
+	last := progress[len(progress)-1]
+	assert.Equal(t, 250, last.Processed)
+	assert.Equal(t, 0, last.Failed)
+	assert.Equal(t, 0, last.Remaining)
+
+	b, err := os.ReadFile(filepath.Join(dir, "chunk-000000200.json"))
+	assert.NoError(t, err)
+
+	var results []postcodesio.BulkPostcodeLookupQueryResponse
+	assert.NoError(t, json.Unmarshal(b, &results))
+	assert.Len(t, results, 50)
+	assert.Equal(t, "AB200 1CD", results[0].Query)
+}
+
+func TestBulkJob_Resume(t *testing.T) {
+	var (
+		failing atomic.Bool
+		calls   atomic.Int32
+	)
+
+	failing.Store(true)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		var req postcodesio.BulkPostCodeLookupRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		if failing.Load() && req.Postcodes[0] == "AB100 1CD" {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"status":500}`)
+
+			return
+		}
+
+		results := make([]string, len(req.Postcodes))
+		for i, p := range req.Postcodes {
+			results[i] = fmt.Sprintf(`{"query":%q,"result":{"postcode":%q}}`, p, p)
+		}
+
+		fmt.Fprintf(w, `{"status":200,"result":[%s]}`, strings.Join(results, ","))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+
+	var last postcodesio.Progress
+
+	job := postcodesio.BulkJob{
+		Client:         postcodesio.NewTestClient(srv.URL),
+		Postcodes:      testPostcodes(300),
+		Sink:           postcodesio.DirSink{Dir: dir},
+		CheckpointPath: filepath.Join(dir, "checkpoint"),
+		OnProgress: func(p postcodesio.Progress) {
+			last = p
+		},
+	}
+
+	err := job.Run(context.Background())
+	assert.ErrorIs(t, err, postcodesio.ErrJobIncomplete)
+	assert.EqualValues(t, 3, calls.Load())
+	assert.Equal(t, postcodesio.Progress{Processed: 200, Failed: 100}, last)
+
+	failing.Store(false)
+	calls.Store(0)
+
+	err = job.Run(context.Background())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, calls.Load(), "completed chunks must not be queried again")
+	assert.Equal(t, 300, last.Processed)
+	assert.Equal(t, 0, last.Remaining)
+
+	files, err := filepath.Glob(filepath.Join(dir, "chunk-*.json"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+}
+
+func TestBulkJob_CheckpointMismatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkpoint")
+	assert.NoError(t, os.WriteFile(path, []byte("# postcodesio bulk job checkpoint chunk_size=100 total=10\n0\n"), 0o600))
+
+	job := postcodesio.BulkJob{
+		Client:         postcodesio.New(),
+		Postcodes:      testPostcodes(20),
+		Sink:           postcodesio.DirSink{Dir: dir},
+		CheckpointPath: path,
+	}
+
+	err := job.Run(context.Background())
+	assert.ErrorIs(t, err, postcodesio.ErrCheckpointMismatch)
+}
+
+func TestBulkJob_CheckpointOfDifferentPostcodes(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		batchSizes []int
+	)
+
+	srv := newBulkLookupServer(t, &batchSizes, &mu)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	job := postcodesio.BulkJob{
+		Client:         postcodesio.NewTestClient(srv.URL),
+		Postcodes:      testPostcodes(150),
+		Sink:           postcodesio.DirSink{Dir: dir},
+		CheckpointPath: filepath.Join(dir, "checkpoint"),
+	}
+
+	assert.NoError(t, job.Run(context.Background()))
+
+	job.Postcodes = testPostcodes(150)
+	job.Postcodes[149] = "XX1 1XX"
+
+	err := job.Run(context.Background())
+	assert.ErrorIs(t, err, postcodesio.ErrCheckpointMismatch, "a list of the same length must not resume the checkpoint")
+	assert.Equal(t, []int{100, 50}, batchSizes)
+}
+
+func TestBulkJob_BlankPostcodes(t *testing.T) {
+	var queried []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req postcodesio.BulkPostCodeLookupRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		queried = append(queried, req.Postcodes...)
+
+		results := make([]string, len(req.Postcodes))
+		for i, p := range req.Postcodes {
+			results[i] = fmt.Sprintf(`{"query":%q,"result":{"postcode":%q}}`, p, p)
+		}
+
+		fmt.Fprintf(w, `{"status":200,"result":[%s]}`, strings.Join(results, ","))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+
+	job := postcodesio.BulkJob{
+		Client:         postcodesio.NewTestClient(srv.URL),
+		Postcodes:      []string{"NW1 6XE", "", "SW1A 2AA", " "},
+		Sink:           postcodesio.DirSink{Dir: dir},
+		CheckpointPath: filepath.Join(dir, "checkpoint"),
+	}
+
+	assert.NoError(t, job.Run(context.Background()))
+	assert.Equal(t, []string{"NW1 6XE", "SW1A 2AA"}, queried)
+
+	b, err := os.ReadFile(filepath.Join(dir, "chunk-000000000.json"))
+	assert.NoError(t, err)
+
+	var results []postcodesio.BulkPostcodeLookupQueryResponse
+	assert.NoError(t, json.Unmarshal(b, &results))
+
+	if assert.Len(t, results, 4) {
+		assert.Equal(t, "NW1 6XE", results[0].Result.Postcode)
+		assert.Equal(t, "", results[1].Query)
+		assert.Empty(t, results[1].Result.Postcode)
+		assert.Equal(t, "SW1A 2AA", results[2].Result.Postcode)
+		assert.Equal(t, " ", results[3].Query)
+		assert.Empty(t, results[3].Result.Postcode)
+	}
+}
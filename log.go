@@ -0,0 +1,154 @@
+package postcodesio
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// hashLength is the number of hex characters of the hash kept in the logs.
+	hashLength = 8
+	// logKeySize is the size in bytes of the key of the hashes kept in the logs.
+	logKeySize = 32
+)
+
+// safeQueryParams are query parameters that carry no personal data and are logged verbatim.
+var safeQueryParams = map[string]bool{
+	"limit":      true,
+	"radius":     true,
+	"widesearch": true,
+	"filter":     true,
+}
+
+// logRequest logs the outcome of a http request, if a logger is set.
+func (c *Client) logRequest(req *http.Request, status int, duration time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", c.redactURL(req.URL)),
+		slog.Duration("duration", duration),
+	}
+
+	if status > 0 {
+		attrs = append(attrs, slog.Int("status", status))
+	}
+
+	switch {
+	case err != nil:
+		attrs = append(attrs, slog.String("error", c.redactKey(c.redactError(err))))
+		c.logger.LogAttrs(req.Context(), slog.LevelWarn, "postcodesio request failed", attrs...)
+	case status >= http.StatusBadRequest:
+		c.logger.LogAttrs(req.Context(), slog.LevelWarn, "postcodesio request returned an error status", attrs...)
+	default:
+		c.logger.LogAttrs(req.Context(), slog.LevelDebug, "postcodesio request", attrs...)
+	}
+}
+
+// redactURL returns the path and query of u with postcodes and coordinates hashed.
+// The first path segment identifies the API resource and is kept verbatim.
+func (c *Client) redactURL(u *url.URL) string {
+	segments := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		segments[i] = c.hash(segments[i])
+	}
+
+	redacted := "/" + strings.Join(segments, "/")
+
+	query := u.Query()
+	if len(query) == 0 {
+		return redacted
+	}
+
+	for key, values := range query {
+		if safeQueryParams[key] {
+			continue
+		}
+
+		for i := range values {
+			values[i] = c.hash(values[i])
+		}
+	}
+
+	return redacted + "?" + query.Encode()
+}
+
+// redactError returns the error message with the request URL redacted.
+func (c *Client) redactError(err error) string {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err.Error()
+	}
+
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return fmt.Sprintf("%s: %s", urlErr.Op, urlErr.Err)
+	}
+
+	return fmt.Sprintf("%s %q: %s", urlErr.Op, c.redactURL(u), urlErr.Err)
+}
+
+// hash returns a short HMAC of a normalised (case and space insensitive) value, so that the same postcode can be
+// correlated across the log entries of the Client without being disclosed. The key is random and never logged, so
+// that hashes cannot be reversed with a table of all the postcodes, nor correlated across processes.
+func (c *Client) hash(value string) string {
+	mac := hmac.New(sha256.New, c.logKey)
+	mac.Write([]byte(strings.ToUpper(strings.ReplaceAll(value, " ", ""))))
+
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil))[:hashLength]
+}
+
+// newLogKey returns a random key for the hashes kept in the logs.
+func newLogKey() []byte {
+	key := make([]byte, logKeySize)
+	// crypto/rand.Read does not fail on supported platforms.
+	_, _ = rand.Read(key)
+
+	return key
+}
+
+// redactedError is an error whose message has the API key redacted.
+type redactedError struct {
+	msg string
+	err error
+}
+
+// Error implements error.
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactAPIKey returns err with the API key of the Client, if any, redacted from its message.
+func (c *Client) redactAPIKey(err error) error {
+	msg := c.redactKey(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+
+	return &redactedError{msg: msg, err: err}
+}
+
+// redactKey returns s with the API key of the Client, if any, redacted.
+func (c *Client) redactKey(s string) string {
+	if c.apiKey == "" {
+		return s
+	}
+
+	return strings.ReplaceAll(s, c.apiKey, "[REDACTED]")
+}
@@ -0,0 +1,128 @@
+package postcodesio_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+)
+
+// newCapturingClient returns a client that records the request URI of each request without sending it, answering
+// with an empty result.
+func newCapturingClient(uri *string) *postcodesio.Client {
+	return postcodesio.NewTestClient("http://postcodes.test", postcodesio.WithTransport(roundTripFunc(
+		func(r *http.Request) (*http.Response, error) {
+			*uri = r.URL.RequestURI()
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"status":200,"result":null}`)),
+				Request:    r,
+			}, nil
+		},
+	)))
+}
+
+func FuzzPostcodeLookup(f *testing.F) {
+	for _, seed := range []string{
+		"NW1 6XE", "../outcodes/NW1", "?x=1", "#x", ".", "..", "%2e%2e", "a/b", "NW1%206XE", "NW1\n6XE", "\x00", "é",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, postcode string) {
+		var uri string
+
+		_, err := newCapturingClient(&uri).PostcodeLookup(context.Background(), postcode)
+		if strings.TrimSpace(postcode) == "" {
+			if !errors.Is(err, postcodesio.ErrInvalidRequest) {
+				t.Fatalf("blank postcode %q: got error %v", postcode, err)
+			}
+
+			return
+		}
+
+		if err != nil {
+			t.Fatalf("postcode %q: %v", postcode, err)
+		}
+
+		// The request is parsed as a server would.
+		u, err := url.ParseRequestURI(uri)
+		if err != nil {
+			t.Fatalf("postcode %q: invalid request URI %q: %v", postcode, uri, err)
+		}
+
+		if u.RawQuery != "" || u.ForceQuery || u.Fragment != "" {
+			t.Fatalf("postcode %q: query injected in %q", postcode, uri)
+		}
+
+		escaped := u.EscapedPath()
+		if path.Clean(escaped) != escaped {
+			t.Fatalf("postcode %q: path %q is not canonical", postcode, escaped)
+		}
+
+		segment, ok := strings.CutPrefix(escaped, "/postcodes/")
+		if !ok || strings.Contains(segment, "/") {
+			t.Fatalf("postcode %q: path %q escapes /postcodes/:postcode", postcode, escaped)
+		}
+
+		if got, err := url.PathUnescape(segment); err != nil || got != postcode {
+			t.Fatalf("postcode %q: path segment %q decodes to %q, %v", postcode, segment, got, err)
+		}
+	})
+}
+
+func FuzzReverseGeocoding(f *testing.F) {
+	f.Add(51.523659, -0.158541, 10, 4.5, true)
+	f.Add(51.5, -0.1, 0, 0.0, false)
+	f.Add(math.Inf(1), math.NaN(), -1, -1.0, false)
+
+	f.Fuzz(func(t *testing.T, lat, lon float64, limit int, radius float64, wide bool) {
+		var uri string
+
+		request := postcodesio.ReverseGeocodingRequest{Latitude: lat, Longitude: lon, Limit: limit, Radius: radius, WideSearch: wide}
+
+		_, err := newCapturingClient(&uri).ReverseGeocoding(context.Background(), request)
+		if request.Validate() != nil {
+			if !errors.Is(err, postcodesio.ErrInvalidRequest) || uri != "" {
+				t.Fatalf("invalid request %+v: got error %v, sent %q", request, err, uri)
+			}
+
+			return
+		}
+
+		if err != nil {
+			t.Fatalf("request %+v: %v", request, err)
+		}
+
+		u, err := url.ParseRequestURI(uri)
+		if err != nil || u.Path != "/postcodes" {
+			t.Fatalf("request %+v: unexpected request URI %q: %v", request, uri, err)
+		}
+
+		expected := url.Values{"lat": {strconv.FormatFloat(lat, 'g', -1, 64)}, "lon": {strconv.FormatFloat(lon, 'g', -1, 64)}}
+		if limit > 0 {
+			expected.Set("limit", strconv.Itoa(limit))
+		}
+
+		if radius > 0 {
+			expected.Set("radius", strconv.FormatFloat(radius, 'g', -1, 64))
+		}
+
+		if wide {
+			expected.Set("widesearch", "true")
+		}
+
+		if got := u.Query(); got.Encode() != expected.Encode() {
+			t.Fatalf("request %+v: query %q, expected %q", request, got.Encode(), expected.Encode())
+		}
+	})
+}
@@ -2,7 +2,10 @@ package postcodesio_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -58,10 +61,12 @@ func TestBulkPostcodeLookup(t *testing.T) {
 			name: "with filter",
 			givenRequest: postcodesio.BulkPostCodeLookupRequest{
 				Postcodes: []string{"NW1 6XE"},
-				Filters:   []string{"postcode", "country", "longitude", "latitude"},
+				Filters: []postcodesio.Field{
+					postcodesio.FieldPostcode, postcodesio.FieldCountry, postcodesio.FieldLongitude, postcodesio.FieldLatitude,
+				},
 			},
 			responseBody: `{"status":200,"result":[{"query":"NW1 6XE","result":{"postcode":"NW1 6XE","country":"England","longitude":-0.158541,"latitude":51.523659}}]}`, //nolint: lll
-			expectedURL:  "/postcodes?filter=postcode,country,longitude,latitude",
+			expectedURL:  "/postcodes?filter=postcode%2Ccountry%2Clongitude%2Clatitude",
 			expectedResponse: &postcodesio.BulkPostcodeLookupResponse{
 				Status: 200,
 				Result: []postcodesio.BulkPostcodeLookupQueryResponse{
@@ -70,8 +75,8 @@ func TestBulkPostcodeLookup(t *testing.T) {
 						Result: postcodesio.Postcode{
 							Postcode:  "NW1 6XE",
 							Country:   "England",
-							Longitude: -0.158541,
-							Latitude:  51.523659,
+							Longitude: postcodesio.Some(-0.158541),
+							Latitude:  postcodesio.Some(51.523659),
 						},
 					},
 				},
@@ -112,7 +117,7 @@ func TestReverseGeocoding(t *testing.T) {
 				Longitude: -0.158541,
 			},
 			responseBody: `{"status":200,"result":[{"postcode":"NW1 6XE","quality":1,"eastings":527850,"northings":182134,"country":"England","nhs_ha":"London","longitude":-0.158541,"latitude":51.523659,"european_electoral_region":"London","primary_care_trust":"Westminster","region":"London","lsoa":"Westminster 008B","msoa":"Westminster 008","incode":"6XE","outcode":"NW1","parliamentary_constituency":"Cities of London and Westminster","admin_district":"Westminster","parish":"Westminster, unparished area","admin_county":null,"admin_ward":"Regent's Park","ced":null,"ccg":"NHS North West London","nuts":"Westminster","codes":{"admin_district":"E09000033","admin_county":"E99999999","admin_ward":"E05013805","parish":"E43000236","parliamentary_constituency":"E14000639","ccg":"E38000256","ccg_id":"W2U3Z","ced":"E99999999","nuts":"TLI32","lsoa":"E01004660","msoa":"E02000967","lau2":"E09000033"},"distance":16.25329604}]}`, //nolint: lll
-			expectedURL:  "/postcodes?lon=-0.158541&lat=51.523659",
+			expectedURL:  "/postcodes?lat=51.523659&lon=-0.158541",
 			expectedResponse: &postcodesio.ReverseGeocodingResponse{
 				Status: 200,
 				Result: []postcodesio.ReversePostcode{
@@ -133,7 +138,7 @@ func TestReverseGeocoding(t *testing.T) {
 				WideSearch: true,
 			},
 			responseBody: `{"status":200,"result":[{"postcode":"NW1 6XE","quality":1,"eastings":527850,"northings":182134,"country":"England","nhs_ha":"London","longitude":-0.158541,"latitude":51.523659,"european_electoral_region":"London","primary_care_trust":"Westminster","region":"London","lsoa":"Westminster 008B","msoa":"Westminster 008","incode":"6XE","outcode":"NW1","parliamentary_constituency":"Cities of London and Westminster","admin_district":"Westminster","parish":"Westminster, unparished area","admin_county":null,"admin_ward":"Regent's Park","ced":null,"ccg":"NHS North West London","nuts":"Westminster","codes":{"admin_district":"E09000033","admin_county":"E99999999","admin_ward":"E05013805","parish":"E43000236","parliamentary_constituency":"E14000639","ccg":"E38000256","ccg_id":"W2U3Z","ced":"E99999999","nuts":"TLI32","lsoa":"E01004660","msoa":"E02000967","lau2":"E09000033"},"distance":16.25329604}]}`, //nolint: lll
-			expectedURL:  "/postcodes?lon=-0.158541&lat=51.523659&limit=10&radius=4.5&widesearch=true",
+			expectedURL:  "/postcodes?lat=51.523659&limit=10&lon=-0.158541&radius=4.5&widesearch=true",
 			expectedResponse: &postcodesio.ReverseGeocodingResponse{
 				Status: 200,
 				Result: []postcodesio.ReversePostcode{
@@ -144,6 +149,25 @@ func TestReverseGeocoding(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with filter",
+			givenRequest: postcodesio.ReverseGeocodingRequest{
+				Latitude:  51.523659,
+				Longitude: -0.158541,
+				Filters:   []postcodesio.Field{postcodesio.FieldPostcode},
+			},
+			responseBody: `{"status":200,"result":[{"postcode":"NW1 6XE","distance":16.25329604}]}`,
+			expectedURL:  "/postcodes?filter=postcode&lat=51.523659&lon=-0.158541",
+			expectedResponse: &postcodesio.ReverseGeocodingResponse{
+				Status: 200,
+				Result: []postcodesio.ReversePostcode{
+					{
+						Postcode: postcodesio.Postcode{Postcode: "NW1 6XE"},
+						Distance: 16.25329604,
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -163,3 +187,177 @@ func TestReverseGeocoding(t *testing.T) {
 		})
 	}
 }
+
+func TestBulkReverseGeocoding(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/postcodes?filter=postcode%2Clongitude%2Clatitude", r.RequestURI)
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"geolocations":[{"latitude":51.523659,"longitude":-0.158541,"limit":1,"widesearch":true}]}`, string(body))
+
+		fmt.Fprint(w, `{"status":200,"result":[{"query":{"latitude":51.523659,"longitude":-0.158541,"limit":1,"widesearch":true},"result":[{"postcode":"NW1 6XE","longitude":-0.158541,"latitude":51.523659,"distance":16.25329604}]}]}`) //nolint: lll
+	}))
+	defer srv.Close()
+
+	query := postcodesio.Geolocation{Latitude: 51.523659, Longitude: -0.158541, Limit: 1, WideSearch: true}
+	expected := &postcodesio.BulkReverseGeocodingResponse{
+		Status: 200,
+		Result: []postcodesio.BulkReverseGeocodingQueryResponse{
+			{
+				Query: query,
+				Result: []postcodesio.ReversePostcode{
+					{
+						Postcode: postcodesio.Postcode{
+							Postcode:  "NW1 6XE",
+							Longitude: postcodesio.Some(-0.158541),
+							Latitude:  postcodesio.Some(51.523659),
+						},
+						Distance: 16.25329604,
+					},
+				},
+			},
+		},
+	}
+
+	c := postcodesio.NewTestClient(srv.URL)
+	r, err := c.BulkReverseGeocoding(context.Background(), postcodesio.BulkReverseGeocodingRequest{
+		Geolocations: []postcodesio.Geolocation{query},
+		Filters:      []postcodesio.Field{postcodesio.FieldPostcode, postcodesio.FieldLongitude, postcodesio.FieldLatitude},
+	})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, expected, r)
+}
+
+func TestUnknownFilterField(t *testing.T) {
+	c := postcodesio.NewTestClient("http://localhost", postcodesio.WithTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		t.Fatal("request must not be sent")
+
+		return nil, nil
+	})))
+	unknown := []postcodesio.Field{postcodesio.FieldPostcode, "postcod"}
+
+	_, err := c.BulkPostcodeLookup(context.Background(),
+		postcodesio.BulkPostCodeLookupRequest{Postcodes: []string{"NW1 6XE"}, Filters: unknown})
+	assert.ErrorIs(t, err, postcodesio.ErrUnknownField)
+	assert.ErrorContains(t, err, `"postcod"`)
+
+	_, err = c.ReverseGeocoding(context.Background(), postcodesio.ReverseGeocodingRequest{Filters: unknown})
+	assert.ErrorIs(t, err, postcodesio.ErrUnknownField)
+
+	_, err = c.BulkReverseGeocoding(context.Background(), postcodesio.BulkReverseGeocodingRequest{Filters: unknown})
+	assert.ErrorIs(t, err, postcodesio.ErrUnknownField)
+}
+
+func TestBulkReverseGeocodingEach(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/postcodes?filter=postcode", r.RequestURI)
+
+		var req postcodesio.BulkReverseGeocodingRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		if len(req.Geolocations) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":400,"error":"Invalid JSON submitted"}`)
+
+			return
+		}
+
+		fmt.Fprint(w, `{"status":200,"result":[`)
+
+		for i, g := range req.Geolocations {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+
+			fmt.Fprintf(w, `{"query":{"latitude":%g,"longitude":%g},"result":[{"postcode":"P%d","distance":1.5}]}`,
+				g.Latitude, g.Longitude, i)
+		}
+
+		fmt.Fprint(w, `],"extra":{"ignored":[1,2]}}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+	req := postcodesio.BulkReverseGeocodingRequest{
+		Geolocations: []postcodesio.Geolocation{
+			{Latitude: 51.5, Longitude: -0.1}, {Latitude: 51.6, Longitude: -0.2}, {Latitude: 51.7, Longitude: -0.3},
+		},
+	}
+	opt := postcodesio.WithFilter(postcodesio.FieldPostcode)
+
+	var got []string
+
+	err := c.BulkReverseGeocodingEach(context.Background(), req, func(r postcodesio.BulkReverseGeocodingQueryResponse) error {
+		got = append(got, fmt.Sprintf("%g %s %g", r.Query.Latitude, r.Result[0].Postcode.Postcode, r.Result[0].Distance))
+
+		return nil
+	}, opt)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"51.5 P0 1.5", "51.6 P1 1.5", "51.7 P2 1.5"}, got)
+
+	errStop := errors.New("stop")
+	calls := 0
+
+	err = c.BulkReverseGeocodingEach(context.Background(), req, func(postcodesio.BulkReverseGeocodingQueryResponse) error {
+		calls++
+
+		return errStop
+	}, opt)
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+
+	noResult := func(postcodesio.BulkReverseGeocodingQueryResponse) error {
+		t.Fatal("no result expected")
+
+		return nil
+	}
+
+	err = c.BulkReverseGeocodingEach(context.Background(), postcodesio.BulkReverseGeocodingRequest{
+		Geolocations: []postcodesio.Geolocation{{Latitude: 51.5, Longitude: -0.1}},
+	}, noResult, opt)
+	assert.ErrorIs(t, err, postcodesio.ErrUnexpectedStatus)
+	assert.ErrorContains(t, err, "400")
+
+	err = c.BulkReverseGeocodingEach(context.Background(), postcodesio.BulkReverseGeocodingRequest{}, noResult, opt)
+	assert.ErrorIs(t, err, postcodesio.ErrInvalidRequest)
+}
+
+func TestBulkPostcodeLookupEach(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":200,"result":[{"query":"NW16XE","result":{"postcode":"NW1 6XE"}},{"query":"XX","result":null}]}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	var got []postcodesio.BulkPostcodeLookupQueryResponse
+
+	err := c.BulkPostcodeLookupEach(context.Background(), postcodesio.BulkPostCodeLookupRequest{Postcodes: []string{"NW16XE", "XX"}},
+		func(r postcodesio.BulkPostcodeLookupQueryResponse) error {
+			got = append(got, r)
+
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "NW1 6XE", got[0].Result.Postcode)
+	assert.Equal(t, "", got[1].Result.Postcode)
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "<html>Bad Gateway</html>")
+	}))
+	defer bad.Close()
+
+	c = postcodesio.NewTestClient(bad.URL)
+	err = c.BulkPostcodeLookupEach(context.Background(), postcodesio.BulkPostCodeLookupRequest{Postcodes: []string{"NW16XE"}},
+		func(postcodesio.BulkPostcodeLookupQueryResponse) error {
+			t.Fatal("no result expected")
+
+			return nil
+		})
+	assert.ErrorIs(t, err, postcodesio.ErrUnexpectedStatus)
+	assert.ErrorContains(t, err, "502")
+}
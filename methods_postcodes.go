@@ -2,26 +2,23 @@ package postcodesio
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
 // PostcodeLookup This uniquely identifies a postcode.
 // Returns a single postcode entity for a given postcode (case, space insensitive).
 // If no postcode is found it returns "404" response code.
+// The postcode is escaped as a single path segment, and must not be blank.
 // GET https://api.postcodes.io/postcodes/:postcode
-func (c *Client) PostcodeLookup(ctx context.Context, postcode string) (*PostcodeLookupResponse, error) {
-	url := fmt.Sprintf("%s/postcodes/%s", c.baseURL, postcode)
-
-	b, err := c.get(ctx, url)
-	if err != nil {
-		return nil, err
+func (c *Client) PostcodeLookup(ctx context.Context, postcode string, opts ...RequestOption) (*PostcodeLookupResponse, error) {
+	if strings.TrimSpace(postcode) == "" {
+		return nil, &ValidationError{Violations: []Violation{{Field: "postcode", Message: "must not be blank"}}}
 	}
 
 	var r PostcodeLookupResponse
-	err = json.Unmarshal(b, &r)
-	if err != nil {
+	if err := c.get(ctx, "/postcodes/"+pathSegment(postcode), newRequestOptions(opts), &r); err != nil {
 		return nil, err
 	}
 
@@ -30,56 +27,153 @@ func (c *Client) PostcodeLookup(ctx context.Context, postcode string) (*Postcode
 
 // BulkPostcodeLookup Accepts a JSON object containing an array of postcodes. Returns a list of matching postcodes and
 // respective available data. Accepts up to 100 postcodes.
+// Filters restrict the attributes returned for each postcode, and must be known fields. The request, with the filters
+// set with WithFilter, is checked with Validate before it is sent.
 // POST https://api.postcodes.io/postcodes
-func (c *Client) BulkPostcodeLookup(ctx context.Context, bulkRequest BulkPostCodeLookupRequest) (*BulkPostcodeLookupResponse, error) {
-	url := fmt.Sprintf("%s/postcodes", c.baseURL)
-
-	if len(bulkRequest.Filters) > 0 {
-		filters := strings.Join(bulkRequest.Filters, ",")
-		url = fmt.Sprintf("%s?filter=%s", url, filters)
-	}
+func (c *Client) BulkPostcodeLookup(
+	ctx context.Context, bulkRequest BulkPostCodeLookupRequest, opts ...RequestOption,
+) (*BulkPostcodeLookupResponse, error) {
+	o := newRequestOptions(opts)
 
-	b, err := c.post(ctx, url, bulkRequest)
-	if err != nil {
+	bulkRequest.Filters = o.withFilters(bulkRequest.Filters)
+	if err := bulkRequest.Validate(); err != nil {
 		return nil, err
 	}
 
+	path := bulkPath(bulkRequest.Filters)
+
 	var r BulkPostcodeLookupResponse
-	err = json.Unmarshal(b, &r)
-	if err != nil {
+	if err := c.post(ctx, path, bulkRequest, o, &r); err != nil {
 		return nil, err
 	}
 
 	return &r, nil
 }
 
+// BulkPostcodeLookupEach is like BulkPostcodeLookup, but calls fn with each query result as soon as it is decoded,
+// instead of holding the whole response in memory. It stops at the first error returned by fn, and fails with
+// ErrUnexpectedStatus if the API does not respond with status 200.
+func (c *Client) BulkPostcodeLookupEach(
+	ctx context.Context, bulkRequest BulkPostCodeLookupRequest, fn func(BulkPostcodeLookupQueryResponse) error, opts ...RequestOption,
+) error {
+	o := newRequestOptions(opts)
+
+	bulkRequest.Filters = o.withFilters(bulkRequest.Filters)
+	if err := bulkRequest.Validate(); err != nil {
+		return err
+	}
+
+	path := bulkPath(bulkRequest.Filters)
+
+	return postEach(ctx, c, path, bulkRequest, o, fn)
+}
+
 // ReverseGeocoding Returns nearest postcodes for a given longitude and latitude.
+// Filters restrict the attributes returned for each postcode, and must be known fields. The request, with the filters
+// set with WithFilter, is checked with Validate before it is sent.
 // GET https://api.postcodes.io/postcodes?lon=:longitude&lat=:latitude
-func (c *Client) ReverseGeocoding(ctx context.Context, request ReverseGeocodingRequest) (*ReverseGeocodingResponse, error) {
-	url := fmt.Sprintf("%s/postcodes?lon=%g&lat=%g", c.baseURL, request.Longitude, request.Latitude)
+func (c *Client) ReverseGeocoding(
+	ctx context.Context, request ReverseGeocodingRequest, opts ...RequestOption,
+) (*ReverseGeocodingResponse, error) {
+	o := newRequestOptions(opts)
+
+	request.Filters = o.withFilters(request.Filters)
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("lon", formatFloat(request.Longitude))
+	query.Set("lat", formatFloat(request.Latitude))
 
 	if request.Limit > 0 {
-		url = fmt.Sprintf("%s&limit=%d", url, request.Limit)
+		query.Set("limit", strconv.Itoa(request.Limit))
 	}
 
 	if request.Radius > 0 {
-		url = fmt.Sprintf("%s&radius=%g", url, request.Radius)
+		query.Set("radius", formatFloat(request.Radius))
 	}
 
 	if request.WideSearch {
-		url = fmt.Sprintf("%s&widesearch=true", url)
+		query.Set("widesearch", "true")
 	}
 
-	b, err := c.get(ctx, url)
-	if err != nil {
-		return nil, err
+	if len(request.Filters) > 0 {
+		query.Set("filter", joinFields(request.Filters))
 	}
 
 	var r ReverseGeocodingResponse
-	err = json.Unmarshal(b, &r)
-	if err != nil {
+	if err := c.get(ctx, "/postcodes?"+query.Encode(), o, &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// BulkReverseGeocoding Bulk translates geolocations into Postcodes. Accepts up to 100 geolocations.
+// Filters restrict the attributes returned for each postcode, and must be known fields. The request, with the filters
+// set with WithFilter, is checked with Validate before it is sent.
+// POST https://api.postcodes.io/postcodes
+func (c *Client) BulkReverseGeocoding(
+	ctx context.Context, bulkRequest BulkReverseGeocodingRequest, opts ...RequestOption,
+) (*BulkReverseGeocodingResponse, error) {
+	o := newRequestOptions(opts)
+
+	bulkRequest.Filters = o.withFilters(bulkRequest.Filters)
+	if err := bulkRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := bulkPath(bulkRequest.Filters)
+
+	var r BulkReverseGeocodingResponse
+	if err := c.post(ctx, path, bulkRequest, o, &r); err != nil {
 		return nil, err
 	}
 
 	return &r, nil
 }
+
+// BulkReverseGeocodingEach is like BulkReverseGeocoding, but calls fn with each query result as soon as it is decoded,
+// instead of holding the whole response in memory. It stops at the first error returned by fn, and fails with
+// ErrUnexpectedStatus if the API does not respond with status 200.
+func (c *Client) BulkReverseGeocodingEach(
+	ctx context.Context, bulkRequest BulkReverseGeocodingRequest, fn func(BulkReverseGeocodingQueryResponse) error,
+	opts ...RequestOption,
+) error {
+	o := newRequestOptions(opts)
+
+	bulkRequest.Filters = o.withFilters(bulkRequest.Filters)
+	if err := bulkRequest.Validate(); err != nil {
+		return err
+	}
+
+	path := bulkPath(bulkRequest.Filters)
+
+	return postEach(ctx, c, path, bulkRequest, o, fn)
+}
+
+// bulkPath returns the path of the bulk methods with the filters.
+func bulkPath(filters []Field) string {
+	if len(filters) == 0 {
+		return "/postcodes"
+	}
+
+	return "/postcodes?" + url.Values{"filter": {joinFields(filters)}}.Encode()
+}
+
+// pathSegment escapes s as a single path segment, so that it cannot change the requested resource. "." and ".." are
+// percent-encoded too, as they would otherwise be resolved as the current and parent paths.
+func pathSegment(s string) string {
+	escaped := url.PathEscape(s)
+	if escaped == "." || escaped == ".." {
+		return strings.ReplaceAll(escaped, ".", "%2E")
+	}
+
+	return escaped
+}
+
+// formatFloat formats a query parameter with the fewest digits that represent f exactly.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
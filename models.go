@@ -1,31 +1,87 @@
 package postcodesio
 
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/leandrorondon/postcodesio-go/geo"
+)
+
 // Postcode (Ordnance Survey Postcode Directory Dataset).
 // Data points returned by the /postcodes and /outcodes API.
+// Attributes that can be null in the API are represented as Optional.
+// Attributes not known by this client are kept in Extra.
 type Postcode struct {
-	Postcode                  string  `json:"postcode"`
-	Outcode                   string  `json:"outcode"`
-	Incode                    string  `json:"incode"`
-	Quality                   int     `json:"quality"`
-	Eastings                  int     `json:"eastings,omitempty"`
-	Northings                 int     `json:"northings,omitempty"`
-	Country                   string  `json:"country"`
-	NHSHA                     string  `json:"nhs_ha,omitempty"` //nolint: tagliatelle
-	AdminCounty               string  `json:"admin_county,omitempty"`
-	AdminDistrict             string  `json:"admin_district,omitempty"`
-	AdminWard                 string  `json:"admin_ward,omitempty"`
-	Longitude                 float64 `json:"longitude,omitempty"`
-	Latitude                  float64 `json:"latitude,omitempty"`
-	ParliamentaryConstituency string  `json:"parliamentary_constituency,omitempty"`
-	PrimaryCareTrust          string  `json:"primary_care_trust,omitempty"`
-	Region                    string  `json:"region,omitempty"`
-	Parish                    string  `json:"parish,omitempty"`
-	LSOA                      string  `json:"lsoa,omitempty"`
-	MSOA                      string  `json:"msoa,omitempty"`
-	CED                       string  `json:"ced,omitempty"`
-	CCG                       string  `json:"ccg,omitempty"`
-	NUTS                      string  `json:"nuts,omitempty"`
-	Codes                     Codes   `json:"codes"`
+	Postcode                      string            `json:"postcode"`
+	Outcode                       string            `json:"outcode"`
+	Incode                        string            `json:"incode"`
+	Quality                       int               `json:"quality"`
+	Eastings                      Optional[int]     `json:"eastings"`
+	Northings                     Optional[int]     `json:"northings"`
+	Country                       string            `json:"country"`
+	NHSHA                         Optional[string]  `json:"nhs_ha"` //nolint: tagliatelle
+	AdminCounty                   Optional[string]  `json:"admin_county"`
+	AdminDistrict                 Optional[string]  `json:"admin_district"`
+	AdminWard                     Optional[string]  `json:"admin_ward"`
+	Longitude                     Optional[float64] `json:"longitude"`
+	Latitude                      Optional[float64] `json:"latitude"`
+	ParliamentaryConstituency     Optional[string]  `json:"parliamentary_constituency"`
+	ParliamentaryConstituency2024 Optional[string]  `json:"parliamentary_constituency_2024"` //nolint: tagliatelle
+	EuropeanElectoralRegion       Optional[string]  `json:"european_electoral_region"`
+	PrimaryCareTrust              Optional[string]  `json:"primary_care_trust"`
+	Region                        Optional[string]  `json:"region"`
+	Parish                        Optional[string]  `json:"parish"`
+	LSOA                          Optional[string]  `json:"lsoa"`
+	MSOA                          Optional[string]  `json:"msoa"`
+	LSOA11                        Optional[string]  `json:"lsoa11"`
+	MSOA11                        Optional[string]  `json:"msoa11"`
+	LSOA21                        Optional[string]  `json:"lsoa21"`
+	MSOA21                        Optional[string]  `json:"msoa21"`
+	CED                           Optional[string]  `json:"ced"`
+	CCG                           Optional[string]  `json:"ccg"`
+	NUTS                          Optional[string]  `json:"nuts"`
+	PFA                           Optional[string]  `json:"pfa"`
+	NHSRegion                     Optional[string]  `json:"nhs_region"` //nolint: tagliatelle
+	DateOfIntroduction            Optional[string]  `json:"date_of_introduction"`
+	Codes                         Codes             `json:"codes"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown attributes in Extra.
+func (p *Postcode) UnmarshalJSON(data []byte) error {
+	type plain Postcode
+
+	extra, err := unmarshalWithExtra(data, (*plain)(p))
+	p.Extra = extra
+
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, including the attributes kept in Extra.
+func (p Postcode) MarshalJSON() ([]byte, error) {
+	type plain Postcode
+
+	return marshalWithExtra(plain(p), p.Extra)
+}
+
+// Location returns the latitude and longitude of the postcode, and whether it is geocoded.
+func (p Postcode) Location() (latitude, longitude float64, ok bool) {
+	if !p.Latitude.Valid || !p.Longitude.Valid {
+		return 0, 0, false
+	}
+
+	return p.Latitude.Value, p.Longitude.Value, true
+}
+
+// GridReference returns the British National Grid eastings and northings of the postcode, and whether it has a grid
+// reference.
+func (p Postcode) GridReference() (eastings, northings int, ok bool) {
+	if !p.Eastings.Valid || !p.Northings.Valid {
+		return 0, 0, false
+	}
+
+	return p.Eastings.Value, p.Northings.Value, true
 }
 
 // ReversePostcode (Ordnance Survey Postcode Directory Dataset).
@@ -35,33 +91,124 @@ type ReversePostcode struct {
 	Distance float64 `json:"distance"`
 }
 
+// reverseDistance holds the attributes ReversePostcode adds to Postcode.
+type reverseDistance struct {
+	Distance float64 `json:"distance"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It is required as the method promoted from Postcode would drop Distance.
+func (p *ReversePostcode) UnmarshalJSON(data []byte) error {
+	if err := p.Postcode.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	var d reverseDistance
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+
+	p.Distance = d.Distance
+
+	delete(p.Extra, "distance")
+
+	if len(p.Extra) == 0 {
+		p.Extra = nil
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. It is required as the method promoted from Postcode would drop Distance.
+func (p ReversePostcode) MarshalJSON() ([]byte, error) {
+	distance, err := json.Marshal(p.Distance)
+	if err != nil {
+		return nil, err
+	}
+
+	type plain Postcode
+
+	return marshalWithExtra(plain(p.Postcode), mergeExtra(p.Extra, "distance", distance))
+}
+
 // Codes Represents an ID or Code associated with the postcode.
+// Codes not known by this client are kept in Extra.
 type Codes struct {
-	AdminCounty   string `json:"admin_county,omitempty"`
-	AdminDistrict string `json:"admin_district,omitempty"`
-	AdminWard     string `json:"admin_ward,omitempty"`
-	Parish        string `json:"parish,omitempty"`
-	CCG           string `json:"ccg,omitempty"`
-	CCGCode       string `json:"ccg_code,omitempty"`
-	NUTS          string `json:"nuts,omitempty"`
-	LAU2          string `json:"lau2,omitempty"`
-	LSOA          string `json:"lsoa,omitempty"`
-	MSOA          string `json:"msoa,omitempty"`
+	AdminCounty                   string `json:"admin_county,omitempty"`
+	AdminDistrict                 string `json:"admin_district,omitempty"`
+	AdminWard                     string `json:"admin_ward,omitempty"`
+	Parish                        string `json:"parish,omitempty"`
+	ParliamentaryConstituency     string `json:"parliamentary_constituency,omitempty"`
+	ParliamentaryConstituency2024 string `json:"parliamentary_constituency_2024,omitempty"` //nolint: tagliatelle
+	PCON                          string `json:"pcon,omitempty"`
+	CCG                           string `json:"ccg,omitempty"`
+	CCGID                         string `json:"ccg_id,omitempty"`
+	CCGCode                       string `json:"ccg_code,omitempty"`
+	CED                           string `json:"ced,omitempty"`
+	NUTS                          string `json:"nuts,omitempty"`
+	LAU2                          string `json:"lau2,omitempty"`
+	LSOA                          string `json:"lsoa,omitempty"`
+	MSOA                          string `json:"msoa,omitempty"`
+	LSOA11                        string `json:"lsoa11,omitempty"`
+	MSOA11                        string `json:"msoa11,omitempty"`
+	LSOA21                        string `json:"lsoa21,omitempty"`
+	MSOA21                        string `json:"msoa21,omitempty"`
+	PFA                           string `json:"pfa,omitempty"`
+	NHSRegion                     string `json:"nhs_region,omitempty"` //nolint: tagliatelle
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown codes in Extra.
+func (c *Codes) UnmarshalJSON(data []byte) error {
+	type plain Codes
+
+	extra, err := unmarshalWithExtra(data, (*plain)(c))
+	c.Extra = extra
+
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, including the codes kept in Extra.
+func (c Codes) MarshalJSON() ([]byte, error) {
+	type plain Codes
+
+	return marshalWithExtra(plain(c), c.Extra)
 }
 
 // Outcode (Ordnance Survey Postcode Directory Dataset).
 // Data returned by the /outcodes API.
+// Attributes not known by this client are kept in Extra.
 type Outcode struct {
-	Outcode       string   `json:"outcode"`
-	Eastings      int      `json:"eastings,omitempty"`
-	Northings     int      `json:"northings,omitempty"`
-	AdminCounty   []string `json:"admin_county"`
-	AdminDistrict []string `json:"admin_district"`
-	AdminWard     []string `json:"admin_ward"`
-	Longitude     float64  `json:"longitude,omitempty"`
-	Latitude      float64  `json:"latitude,omitempty"`
-	Country       []string `json:"country"`
-	Parish        []string `json:"parish"`
+	Outcode                   string            `json:"outcode"`
+	Eastings                  Optional[int]     `json:"eastings"`
+	Northings                 Optional[int]     `json:"northings"`
+	AdminCounty               []string          `json:"admin_county"`
+	AdminDistrict             []string          `json:"admin_district"`
+	AdminWard                 []string          `json:"admin_ward"`
+	Longitude                 Optional[float64] `json:"longitude"`
+	Latitude                  Optional[float64] `json:"latitude"`
+	Country                   []string          `json:"country"`
+	Parish                    []string          `json:"parish"`
+	ParliamentaryConstituency []string          `json:"parliamentary_constituency"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown attributes in Extra.
+func (o *Outcode) UnmarshalJSON(data []byte) error {
+	type plain Outcode
+
+	extra, err := unmarshalWithExtra(data, (*plain)(o))
+	o.Extra = extra
+
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, including the attributes kept in Extra.
+func (o Outcode) MarshalJSON() ([]byte, error) {
+	type plain Outcode
+
+	return marshalWithExtra(plain(o), o.Extra)
 }
 
 // ScottishPostcode (Scottish Postcode Directory).
@@ -89,6 +236,7 @@ type TerminatedPostcode struct {
 
 // Place (Ordnance Survey Open Names Dataset).
 // Data returned by the /places API.
+// Attributes not known by this client are kept in Extra.
 type Place struct {
 	Code                string  `json:"code"`
 	Eastings            int     `json:"eastings"`
@@ -102,13 +250,64 @@ type Place struct {
 	Latitude            float64 `json:"latitude"`
 	LocalType           string  `json:"local_type"`
 	Outcode             string  `json:"outcode"`
-	Name1               string  `json:"name1"`
-	Name1Lang           string  `json:"name1_lang,omitempty"`
-	Name2               string  `json:"name2,omitempty"`
-	Name2Lang           string  `json:"name2_lang,omitempty"`
+	Name1               string  `json:"name_1"`                //nolint: tagliatelle
+	Name1Lang           string  `json:"name_1_lang,omitempty"` //nolint: tagliatelle
+	Name2               string  `json:"name_2,omitempty"`      //nolint: tagliatelle
+	Name2Lang           string  `json:"name_2_lang,omitempty"` //nolint: tagliatelle
 	CountyUnitary       string  `json:"county_unitary,omitempty"`
 	CountyUnitaryType   string  `json:"county_unitary_type,omitempty"`
 	DistrictBorough     string  `json:"district_borough,omitempty"`
 	DistrictBoroughType string  `json:"district_borough_type,omitempty"`
 	Region              string  `json:"region"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown attributes in Extra.
+func (p *Place) UnmarshalJSON(data []byte) error {
+	type plain Place
+
+	extra, err := unmarshalWithExtra(data, (*plain)(p))
+	p.Extra = extra
+
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, including the attributes kept in Extra.
+func (p Place) MarshalJSON() ([]byte, error) {
+	type plain Place
+
+	return marshalWithExtra(plain(p), p.Extra)
+}
+
+// BoundingBoxWGS84 returns the WGS84 bounding box enclosing the British National Grid bounding box of the place.
+func (p Place) BoundingBoxWGS84() geo.BoundingBox {
+	corners := []geo.Point{
+		geo.BNGToWGS84(float64(p.MinEastings), float64(p.MinNorthings)),
+		geo.BNGToWGS84(float64(p.MinEastings), float64(p.MaxNorthings)),
+		geo.BNGToWGS84(float64(p.MaxEastings), float64(p.MinNorthings)),
+		geo.BNGToWGS84(float64(p.MaxEastings), float64(p.MaxNorthings)),
+	}
+
+	box := geo.BoundingBox{Min: corners[0], Max: corners[0]}
+	for _, c := range corners[1:] {
+		box.Min.Latitude = math.Min(box.Min.Latitude, c.Latitude)
+		box.Min.Longitude = math.Min(box.Min.Longitude, c.Longitude)
+		box.Max.Latitude = math.Max(box.Max.Latitude, c.Latitude)
+		box.Max.Longitude = math.Max(box.Max.Longitude, c.Longitude)
+	}
+
+	return box
+}
+
+// mergeExtra returns a copy of extra with an additional member.
+func mergeExtra(extra map[string]json.RawMessage, name string, value json.RawMessage) map[string]json.RawMessage {
+	merged := make(map[string]json.RawMessage, len(extra)+1)
+	for k, v := range extra {
+		merged[k] = v
+	}
+
+	merged[name] = value
+
+	return merged
 }
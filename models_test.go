@@ -0,0 +1,71 @@
+package postcodesio_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostcode_UnknownFields(t *testing.T) {
+	body := `{"postcode":"NW1 6XE","quality":1,"new_attribute":"value","codes":{"admin_district":"E09000033","new_code":"X1"}}`
+
+	var p postcodesio.Postcode
+	err := json.Unmarshal([]byte(body), &p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "NW1 6XE", p.Postcode)
+	assert.Equal(t, map[string]json.RawMessage{"new_attribute": json.RawMessage(`"value"`)}, p.Extra)
+	assert.Equal(t, "E09000033", p.Codes.AdminDistrict)
+	assert.Equal(t, map[string]json.RawMessage{"new_code": json.RawMessage(`"X1"`)}, p.Codes.Extra)
+
+	b, err := json.Marshal(p)
+	assert.NoError(t, err)
+
+	var roundTrip postcodesio.Postcode
+	err = json.Unmarshal(b, &roundTrip)
+	assert.NoError(t, err)
+	assert.Equal(t, p, roundTrip)
+}
+
+func TestReversePostcode_UnknownFields(t *testing.T) {
+	body := `{"postcode":"NW1 6XE","distance":16.25329604,"new_attribute":1}`
+
+	var p postcodesio.ReversePostcode
+	err := json.Unmarshal([]byte(body), &p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "NW1 6XE", p.Postcode.Postcode)
+	assert.Equal(t, 16.25329604, p.Distance)
+	assert.Equal(t, map[string]json.RawMessage{"new_attribute": json.RawMessage(`1`)}, p.Extra)
+
+	b, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"distance":16.25329604`)
+	assert.Contains(t, string(b), `"new_attribute":1`)
+}
+
+func TestPlace_UnmarshalJSON(t *testing.T) {
+	body := `{"code":"osgb4000000074564391","name_1":"Camden Town","local_type":"Suburban Area","min_eastings":528000,"population":1}` //nolint: lll
+
+	var p postcodesio.Place
+	err := json.Unmarshal([]byte(body), &p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Camden Town", p.Name1)
+	assert.Equal(t, 528000, p.MinEastings)
+	assert.Equal(t, map[string]json.RawMessage{"population": json.RawMessage(`1`)}, p.Extra)
+}
+
+func TestPlace_BoundingBoxWGS84(t *testing.T) {
+	p := postcodesio.Place{MinEastings: 527000, MinNorthings: 181000, MaxEastings: 529000, MaxNorthings: 183000}
+
+	box := p.BoundingBoxWGS84()
+
+	assert.True(t, box.Min.Latitude < box.Max.Latitude)
+	assert.True(t, box.Min.Longitude < box.Max.Longitude)
+	assert.True(t, box.Contains(geo.Point{Latitude: 51.523659, Longitude: -0.158541}))
+	assert.False(t, box.Contains(geo.Point{Latitude: 51.50354, Longitude: -0.127695}))
+}
@@ -0,0 +1,65 @@
+package postcodesio
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional represents an attribute that may be null in the API responses.
+// The zero value is a null Optional. An attribute missing from the response, e.g. because it was filtered out, is
+// also decoded as null.
+type Optional[T any] struct {
+	Value T
+	Valid bool
+}
+
+// Some returns a non-null Optional holding value.
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Value: value, Valid: true}
+}
+
+// Get returns the value and whether it is non-null.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Valid
+}
+
+// ValueOr returns the value, or fallback if it is null.
+func (o Optional[T]) ValueOr(fallback T) T {
+	if !o.Valid {
+		return fallback
+	}
+
+	return o.Value
+}
+
+// IsNull reports whether the value is null.
+func (o Optional[T]) IsNull() bool {
+	return !o.Valid
+}
+
+// MarshalJSON implements json.Marshaler, encoding a null Optional as JSON null.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding JSON null as a null Optional.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = Optional[T]{}
+
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*o = Some(value)
+
+	return nil
+}
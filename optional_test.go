@@ -0,0 +1,95 @@
+package postcodesio_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptional_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected postcodesio.Optional[float64]
+	}{
+		{
+			name:     "value",
+			body:     `{"longitude":-0.158541}`,
+			expected: postcodesio.Some(-0.158541),
+		},
+		{
+			name:     "zero value",
+			body:     `{"longitude":0}`,
+			expected: postcodesio.Some(0.0),
+		},
+		{
+			name:     "null",
+			body:     `{"longitude":null}`,
+			expected: postcodesio.Optional[float64]{},
+		},
+		{
+			name:     "missing",
+			body:     `{}`,
+			expected: postcodesio.Optional[float64]{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var v struct {
+				Longitude postcodesio.Optional[float64] `json:"longitude"`
+			}
+
+			err := json.Unmarshal([]byte(test.body), &v)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, v.Longitude)
+		})
+	}
+}
+
+func TestOptional_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal([]postcodesio.Optional[string]{postcodesio.Some(""), {}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `["",null]`, string(b))
+}
+
+func TestOptional_Accessors(t *testing.T) {
+	some := postcodesio.Some("Westminster")
+	value, ok := some.Get()
+	assert.Equal(t, "Westminster", value)
+	assert.True(t, ok)
+	assert.False(t, some.IsNull())
+	assert.Equal(t, "Westminster", some.ValueOr("unknown"))
+
+	var null postcodesio.Optional[string]
+	_, ok = null.Get()
+	assert.False(t, ok)
+	assert.True(t, null.IsNull())
+	assert.Equal(t, "unknown", null.ValueOr("unknown"))
+}
+
+func TestPostcode_Location(t *testing.T) {
+	lat, lon, ok := testPostcode.Location()
+	assert.True(t, ok)
+	assert.Equal(t, 51.523659, lat)
+	assert.Equal(t, -0.158541, lon)
+
+	e, n, ok := testPostcode.GridReference()
+	assert.True(t, ok)
+	assert.Equal(t, 527850, e)
+	assert.Equal(t, 182134, n)
+
+	var p postcodesio.Postcode
+	err := json.Unmarshal([]byte(`{"postcode":"GY1 1AA","longitude":null,"latitude":null,"eastings":null,"northings":null}`), &p)
+	assert.NoError(t, err)
+
+	_, _, ok = p.Location()
+	assert.False(t, ok)
+
+	_, _, ok = p.GridReference()
+	assert.False(t, ok)
+}
@@ -0,0 +1,81 @@
+package postcodesio
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const incodeLen = 3
+
+// postcodeFormat matches a normalised UK postcode, following the format rules of the outward and inward codes.
+var postcodeFormat = regexp.MustCompile(
+	`^(GIR0AA|[A-PR-UWYZ]([0-9]{1,2}|[A-HK-Y][0-9]{1,2}|[0-9][A-HJKPSTUW]|[A-HK-Y][0-9][ABEHMNPRVWXY])[0-9][ABD-HJLNP-UW-Z]{2})$`,
+)
+
+// PostcodeParts is a postcode split into its outward and inward codes.
+type PostcodeParts struct {
+	Outcode string
+	Incode  string
+}
+
+// ParsePostcode parses a postcode (case, space insensitive) according to the UK postcode format rules.
+// It does not check whether the postcode exists.
+func ParsePostcode(postcode string) (PostcodeParts, error) {
+	n := NormalizePostcode(postcode)
+	if !postcodeFormat.MatchString(n) {
+		return PostcodeParts{}, fmt.Errorf("%w: %q", ErrInvalidPostcode, postcode)
+	}
+
+	split := len(n) - incodeLen
+
+	return PostcodeParts{Outcode: n[:split], Incode: n[split:]}, nil
+}
+
+// ValidPostcodeFormat reports whether postcode (case, space insensitive) follows the UK postcode format rules.
+func ValidPostcodeFormat(postcode string) bool {
+	return postcodeFormat.MatchString(NormalizePostcode(postcode))
+}
+
+// NormalizePostcode returns postcode in upper case, without spaces or any other non-alphanumeric characters.
+func NormalizePostcode(postcode string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToUpper(postcode) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// String returns the postcode in its canonical form, e.g. "SW1A 2AA".
+func (p PostcodeParts) String() string {
+	return p.Outcode + " " + p.Incode
+}
+
+// Area returns the postcode area, the leading letters of the outward code, e.g. "SW".
+func (p PostcodeParts) Area() string {
+	return p.Outcode[:areaLen(p.Outcode)]
+}
+
+// District returns the postcode district, which is the outward code, e.g. "SW1A".
+func (p PostcodeParts) District() string {
+	return p.Outcode
+}
+
+// Sector returns the postcode sector, the outward code and the first digit of the inward code, e.g. "SW1A 2".
+func (p PostcodeParts) Sector() string {
+	return p.Outcode + " " + p.Incode[:1]
+}
+
+// areaLen returns the number of leading letters of an outward code.
+func areaLen(outcode string) int {
+	n := 0
+	for n < len(outcode) && outcode[n] >= 'A' && outcode[n] <= 'Z' {
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,42 @@
+package postcodesio_test
+
+import (
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePostcode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		area     string
+		sector   string
+	}{
+		{"sw1a2aa", "SW1A 2AA", "SW", "SW1A 2"},
+		{" nw1 6xe ", "NW1 6XE", "NW", "NW1 6"},
+		{"M1 1AE", "M1 1AE", "M", "M1 1"},
+		{"B33 8TH", "B33 8TH", "B", "B33 8"},
+		{"CR2-6XH", "CR2 6XH", "CR", "CR2 6"},
+		{"DN55 1PT", "DN55 1PT", "DN", "DN55 1"},
+		{"W1A 0AX", "W1A 0AX", "W", "W1A 0"},
+		{"GIR 0AA", "GIR 0AA", "GIR", "GIR 0"},
+	}
+
+	for _, tt := range tests {
+		parts, err := postcodesio.ParsePostcode(tt.input)
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, parts.String())
+		assert.Equal(t, tt.area, parts.Area())
+		assert.Equal(t, parts.Outcode, parts.District())
+		assert.Equal(t, tt.sector, parts.Sector())
+		assert.True(t, postcodesio.ValidPostcodeFormat(tt.input))
+	}
+
+	for _, input := range []string{"", "SW1A", "SW1A 2A", "SWIA 2AA", "SW1A 2CA", "1W1A 2AA", "QW1A 2AA", "SW1A2AAA"} {
+		_, err := postcodesio.ParsePostcode(input)
+		assert.ErrorIs(t, err, postcodesio.ErrInvalidPostcode, input)
+		assert.False(t, postcodesio.ValidPostcodeFormat(input))
+	}
+}
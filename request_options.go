@@ -0,0 +1,74 @@
+package postcodesio
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestOption describes the type for functional options used on a single API method call.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	filters   []Field
+	header    http.Header
+	timeout   time.Duration
+	noHedging bool
+}
+
+// WithFilter is the option to restrict the attributes returned by the call. The fields are added to the filters of the
+// request, and apply to the methods that accept filters: BulkPostcodeLookup, ReverseGeocoding and
+// BulkReverseGeocoding.
+func WithFilter(fields ...Field) RequestOption {
+	return func(o *requestOptions) {
+		o.filters = append(o.filters, fields...)
+	}
+}
+
+// WithHeader is the option to add a header to the http requests of the call.
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Add(key, value)
+	}
+}
+
+// WithRequestTimeout is the option to set a timeout to the call, including failover and hedged requests.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithoutHedging is the option to disable the hedging set with WithHedging for the call.
+func WithoutHedging() RequestOption {
+	return func(o *requestOptions) {
+		o.noHedging = true
+	}
+}
+
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{header: make(http.Header)}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// withFilters returns the request filters followed by the filters set with WithFilter.
+func (o *requestOptions) withFilters(filters []Field) []Field {
+	if len(o.filters) == 0 {
+		return filters
+	}
+
+	return append(append([]Field{}, filters...), o.filters...)
+}
+
+// requiredFilters returns the fields the caller of a method needs in its results if filters are set with WithFilter,
+// to be added to the request filters. Without filters every field is returned, and requiredFilters returns nil.
+func requiredFilters(opts []RequestOption, fields ...Field) []Field {
+	if len(newRequestOptions(opts).filters) == 0 {
+		return nil
+	}
+
+	return fields
+}
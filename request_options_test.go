@@ -0,0 +1,83 @@
+package postcodesio_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFilter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/postcodes?filter=postcode%2Clongitude%2Clatitude", r.RequestURI)
+		fmt.Fprint(w, `{"status":200}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+	_, err := c.BulkPostcodeLookup(
+		context.Background(),
+		postcodesio.BulkPostCodeLookupRequest{Postcodes: []string{"NW1 6XE"}, Filters: []postcodesio.Field{postcodesio.FieldPostcode}},
+		postcodesio.WithFilter(postcodesio.FieldLongitude, postcodesio.FieldLatitude),
+	)
+	assert.NoError(t, err)
+
+	_, err = c.ReverseGeocoding(context.Background(), postcodesio.ReverseGeocodingRequest{}, postcodesio.WithFilter("lat"))
+	assert.ErrorIs(t, err, postcodesio.ErrUnknownField)
+}
+
+func TestWithHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"a", "b"}, r.Header.Values("X-Trace"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		fmt.Fprint(w, `{"status":200}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+	_, err := c.BulkPostcodeLookup(
+		context.Background(),
+		postcodesio.BulkPostCodeLookupRequest{Postcodes: []string{"NW1 6XE"}},
+		postcodesio.WithHeader("X-Trace", "a"),
+		postcodesio.WithHeader("X-Trace", "b"),
+	)
+	assert.NoError(t, err)
+}
+
+func TestWithRequestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	start := time.Now()
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE", postcodesio.WithRequestTimeout(10*time.Millisecond))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestWithoutHedging(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := newSlowFirstServer(30*time.Millisecond, &calls)
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithHedging(postcodesio.HedgePolicy{Delay: time.Millisecond}))
+
+	_, err := c.PostcodeLookup(context.Background(), "NW16XE", postcodesio.WithoutHedging())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, calls.Load())
+}
@@ -0,0 +1,260 @@
+package postcodesio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/leandrorondon/postcodesio-go/geo"
+)
+
+const (
+	// maxReverseLimit is the maximum number of postcodes returned by a reverse geocoding query.
+	maxReverseLimit = 100
+	// maxReverseRadius is the maximum radius in metres of a reverse geocoding query, without wide search.
+	maxReverseRadius = 2000
+	// minSearchCellRadius is the radius in metres under which a search cell is not split further.
+	minSearchCellRadius = 10
+	// maxSearchQueries is the maximum number of reverse geocoding queries of a search.
+	maxSearchQueries = 2000
+	// searchCellMargin enlarges the query circle of a cell, so that it covers the cell despite rounding.
+	searchCellMargin = 1.01
+)
+
+// SearchRadius returns all the postcodes within radius metres of center, nearest first, with their distance to center.
+// See SearchPolygon for how the search is done.
+func (c *Client) SearchRadius(ctx context.Context, center geo.Point, radius float64, opts ...RequestOption) ([]ReversePostcode, error) {
+	found, err := c.search(ctx, circleArea{center: center, radius: radius}, opts)
+
+	results := make([]ReversePostcode, 0, len(found))
+
+	for _, p := range found {
+		lat, lon, _ := p.Location()
+		distance := geo.Distance(center, geo.Point{Latitude: lat, Longitude: lon})
+		results = append(results, ReversePostcode{Postcode: p, Distance: distance})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+
+		return results[i].Postcode.Postcode < results[j].Postcode.Postcode
+	})
+
+	return results, err
+}
+
+// SearchPolygon returns all the postcodes inside the polygon, sorted by postcode.
+// The API caps the radius and the number of results of reverse geocoding queries, so the area is tiled with cells
+// queried with bulk reverse geocoding, and cells returning as many postcodes as the cap are split and queried again.
+// Results are deduplicated and filtered by the exact geometry. If more postcodes than the cap share a cell too small
+// to be split, e.g. many postcodes of a single building, or if the area needs more than 2000 queries, the postcodes
+// found are returned with ErrSearchTruncated.
+// Filters set with WithFilter always include the postcode and its coordinates.
+func (c *Client) SearchPolygon(ctx context.Context, polygon geo.Polygon, opts ...RequestOption) ([]Postcode, error) {
+	found, err := c.search(ctx, polygonArea{polygon}, opts)
+
+	return sortedPostcodes(found), err
+}
+
+// sortedPostcodes returns the postcodes found by search, sorted by postcode.
+func sortedPostcodes(found map[string]Postcode) []Postcode {
+	results := make([]Postcode, 0, len(found))
+	for _, p := range found {
+		results = append(results, p)
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Postcode < results[j].Postcode })
+
+	return results
+}
+
+// searchArea is an area searched for postcodes.
+type searchArea interface {
+	bounds() geo.BoundingBox
+	intersects(b geo.BoundingBox) bool
+	contains(p geo.Point) bool
+}
+
+// search returns the postcodes inside the area by postcode, tiling it with reverse geocoding queries.
+// On error, the postcodes found so far are returned with it.
+func (c *Client) search(ctx context.Context, area searchArea, opts []RequestOption) (map[string]Postcode, error) {
+	filters := requiredFilters(opts, FieldPostcode, FieldLatitude, FieldLongitude)
+	found := make(map[string]Postcode)
+	pending := []geo.BoundingBox{area.bounds()}
+	truncated := false
+	queried := 0
+
+	for len(pending) > 0 {
+		if queried == maxSearchQueries {
+			truncated = true
+
+			break
+		}
+
+		var queries []geo.BoundingBox
+
+		for len(pending) > 0 && len(queries) < min(maxBulkSize, maxSearchQueries-queried) {
+			cell := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+
+			// Cells outside the UK have no postcodes, and their query would be rejected.
+			switch {
+			case !area.intersects(cell) || !cell.Overlaps(ukBounds):
+			case cellRadius(cell) > maxReverseRadius:
+				pending = append(pending, splitCell(cell)...)
+			default:
+				queries = append(queries, cell)
+			}
+		}
+
+		if len(queries) == 0 {
+			continue
+		}
+
+		queried += len(queries)
+
+		geolocations := make([]Geolocation, len(queries))
+		for i, cell := range queries {
+			center := queryCenter(cell)
+			geolocations[i] = Geolocation{
+				Latitude:  center.Latitude,
+				Longitude: center.Longitude,
+				Limit:     maxReverseLimit,
+				Radius:    math.Ceil(cellRadius(cell)),
+			}
+		}
+
+		res, err := c.BulkReverseGeocoding(ctx, BulkReverseGeocodingRequest{Geolocations: geolocations, Filters: filters}, opts...)
+		if err == nil && res.Status != http.StatusOK {
+			err = fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.Status)
+		}
+
+		if err != nil {
+			return found, err
+		}
+
+		for i, r := range res.Result {
+			for _, p := range r.Result {
+				if lat, lon, ok := p.Location(); ok && area.contains(geo.Point{Latitude: lat, Longitude: lon}) {
+					found[p.Postcode.Postcode] = p.Postcode
+				}
+			}
+
+			if len(r.Result) < maxReverseLimit || i >= len(queries) {
+				continue
+			}
+
+			if cellRadius(queries[i]) > minSearchCellRadius {
+				pending = append(pending, splitCell(queries[i])...)
+			} else {
+				truncated = true
+			}
+		}
+	}
+
+	if truncated {
+		return found, ErrSearchTruncated
+	}
+
+	return found, nil
+}
+
+// queryCenter returns the centre of the reverse geocoding query of the cell: the centre of the cell, clamped into
+// ukBounds as the API rejects queries outside the UK.
+func queryCenter(cell geo.BoundingBox) geo.Point {
+	c := cell.Center()
+
+	return geo.Point{
+		Latitude:  math.Max(ukBounds.Min.Latitude, math.Min(c.Latitude, ukBounds.Max.Latitude)),
+		Longitude: math.Max(ukBounds.Min.Longitude, math.Min(c.Longitude, ukBounds.Max.Longitude)),
+	}
+}
+
+// cellRadius returns the radius in metres of the circle centred on the query centre of the cell that covers it.
+// It is larger than half the diagonal of the cell if the query centre is clamped.
+func cellRadius(cell geo.BoundingBox) float64 {
+	center := queryCenter(cell)
+	radius := 0.0
+
+	for _, corner := range []geo.Point{
+		cell.Min, cell.Max,
+		{Latitude: cell.Min.Latitude, Longitude: cell.Max.Longitude},
+		{Latitude: cell.Max.Latitude, Longitude: cell.Min.Longitude},
+	} {
+		radius = math.Max(radius, geo.Distance(center, corner))
+	}
+
+	return radius * searchCellMargin
+}
+
+// splitCell splits a cell into its four quadrants.
+func splitCell(cell geo.BoundingBox) []geo.BoundingBox {
+	c := cell.Center()
+
+	return []geo.BoundingBox{
+		{Min: cell.Min, Max: c},
+		{
+			Min: geo.Point{Latitude: cell.Min.Latitude, Longitude: c.Longitude},
+			Max: geo.Point{Latitude: c.Latitude, Longitude: cell.Max.Longitude},
+		},
+		{
+			Min: geo.Point{Latitude: c.Latitude, Longitude: cell.Min.Longitude},
+			Max: geo.Point{Latitude: cell.Max.Latitude, Longitude: c.Longitude},
+		},
+		{Min: c, Max: cell.Max},
+	}
+}
+
+// circleArea is the area within a radius in metres of a point.
+type circleArea struct {
+	center geo.Point
+	radius float64
+}
+
+func (a circleArea) bounds() geo.BoundingBox {
+	r := a.radius * searchCellMargin
+	north := geo.Destination(a.center, 0, r)
+	east := geo.Destination(a.center, 90, r)   //nolint: gomnd
+	south := geo.Destination(a.center, 180, r) //nolint: gomnd
+	west := geo.Destination(a.center, 270, r)  //nolint: gomnd
+
+	return geo.BoundingBox{
+		Min: geo.Point{Latitude: south.Latitude, Longitude: west.Longitude},
+		Max: geo.Point{Latitude: north.Latitude, Longitude: east.Longitude},
+	}
+}
+
+// intersects reports whether the point of b nearest to the centre is within the radius.
+func (a circleArea) intersects(b geo.BoundingBox) bool {
+	nearest := geo.Point{
+		Latitude:  math.Max(b.Min.Latitude, math.Min(a.center.Latitude, b.Max.Latitude)),
+		Longitude: math.Max(b.Min.Longitude, math.Min(a.center.Longitude, b.Max.Longitude)),
+	}
+
+	return geo.Distance(a.center, nearest) <= a.radius*searchCellMargin
+}
+
+func (a circleArea) contains(p geo.Point) bool {
+	return geo.Distance(a.center, p) <= a.radius
+}
+
+// polygonArea is the area inside a polygon.
+type polygonArea struct {
+	polygon geo.Polygon
+}
+
+func (a polygonArea) bounds() geo.BoundingBox {
+	return a.polygon.BoundingBox()
+}
+
+func (a polygonArea) intersects(b geo.BoundingBox) bool {
+	return a.polygon.Intersects(b)
+}
+
+func (a polygonArea) contains(p geo.Point) bool {
+	return a.polygon.Contains(p)
+}
@@ -0,0 +1,248 @@
+package postcodesio_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// searchPoint is a postcode of the dataset of newReverseGeocodingServer.
+type searchPoint struct {
+	postcode string
+	point    geo.Point
+}
+
+// newReverseGeocodingServer returns a server answering bulk reverse geocoding queries over the dataset, with the
+// limit and radius caps of the API, counting the queries it gets.
+func newReverseGeocodingServer(t *testing.T, dataset []searchPoint, queries *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req postcodesio.BulkReverseGeocodingRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.LessOrEqual(t, len(req.Geolocations), 100)
+		queries.Add(int32(len(req.Geolocations)))
+
+		results := make([]string, len(req.Geolocations))
+
+		for i, g := range req.Geolocations {
+			assert.LessOrEqual(t, g.Limit, 100)
+			assert.LessOrEqual(t, g.Radius, 2000.0)
+
+			center := geo.Point{Latitude: g.Latitude, Longitude: g.Longitude}
+
+			type match struct {
+				p searchPoint
+				d float64
+			}
+
+			var within []match
+
+			for _, p := range dataset {
+				if d := geo.Distance(center, p.point); d <= g.Radius {
+					within = append(within, match{p: p, d: d})
+				}
+			}
+
+			sort.Slice(within, func(i, j int) bool { return within[i].d < within[j].d })
+
+			matches := []string{}
+
+			for _, m := range within[:min(len(within), g.Limit)] {
+				matches = append(matches, fmt.Sprintf(`{"postcode":%q,"latitude":%g,"longitude":%g,"distance":%g}`,
+					m.p.postcode, m.p.point.Latitude, m.p.point.Longitude, m.d))
+			}
+
+			results[i] = fmt.Sprintf(`{"query":{"latitude":%g,"longitude":%g},"result":[%s]}`,
+				g.Latitude, g.Longitude, strings.Join(matches, ","))
+		}
+
+		fmt.Fprintf(w, `{"status":200,"result":[%s]}`, strings.Join(results, ","))
+	}))
+}
+
+// randomDataset returns n postcodes spread randomly within about 4 km of NW1 6XE.
+func randomDataset(n int) []searchPoint {
+	rnd := rand.New(rand.NewSource(1)) //nolint: gosec
+
+	dataset := make([]searchPoint, n)
+	for i := range dataset {
+		dataset[i] = searchPoint{
+			postcode: fmt.Sprintf("PC%05d", i),
+			point:    geo.Point{Latitude: 51.49 + rnd.Float64()*0.07, Longitude: -0.21 + rnd.Float64()*0.11},
+		}
+	}
+
+	return dataset
+}
+
+func TestSearchRadius(t *testing.T) {
+	var queries atomic.Int32
+
+	dataset := randomDataset(3000)
+	srv := newReverseGeocodingServer(t, dataset, &queries)
+	defer srv.Close()
+
+	center := geo.Point{Latitude: 51.523659, Longitude: -0.158541}
+
+	var expected []string
+
+	for _, p := range dataset {
+		if geo.Distance(center, p.point) <= 2500 {
+			expected = append(expected, p.postcode)
+		}
+	}
+
+	c := postcodesio.NewTestClient(srv.URL)
+	results, err := c.SearchRadius(context.Background(), center, 2500)
+	assert.NoError(t, err)
+
+	var got []string
+
+	for i, r := range results {
+		got = append(got, r.Postcode.Postcode)
+
+		assert.LessOrEqual(t, r.Distance, 2500.0)
+
+		if i > 0 {
+			assert.LessOrEqual(t, results[i-1].Distance, r.Distance)
+		}
+	}
+
+	sort.Strings(expected)
+	sort.Strings(got)
+	assert.Greater(t, len(expected), 1000)
+	assert.Equal(t, expected, got)
+	assert.Greater(t, queries.Load(), int32(10), "dense cells must be split")
+}
+
+func TestSearchPolygon(t *testing.T) {
+	var queries atomic.Int32
+
+	dataset := randomDataset(3000)
+	srv := newReverseGeocodingServer(t, dataset, &queries)
+	defer srv.Close()
+
+	polygon := geo.Polygon{{
+		{Latitude: 51.50, Longitude: -0.20},
+		{Latitude: 51.50, Longitude: -0.11},
+		{Latitude: 51.55, Longitude: -0.15},
+	}}
+
+	var expected []string
+
+	for _, p := range dataset {
+		if polygon.Contains(p.point) {
+			expected = append(expected, p.postcode)
+		}
+	}
+
+	sort.Strings(expected)
+
+	var filter string
+
+	c := postcodesio.NewTestClient(srv.URL, postcodesio.WithTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		filter = r.URL.Query().Get("filter")
+
+		return http.DefaultTransport.RoundTrip(r)
+	})))
+	results, err := c.SearchPolygon(context.Background(), polygon, postcodesio.WithFilter(postcodesio.FieldCountry))
+	assert.NoError(t, err)
+	assert.Equal(t, "postcode,latitude,longitude,country", filter)
+
+	got := make([]string, len(results))
+	for i, p := range results {
+		got[i] = p.Postcode
+	}
+
+	assert.Greater(t, len(expected), 500)
+	assert.Equal(t, expected, got)
+}
+
+func TestSearchRadius_Truncated(t *testing.T) {
+	var queries atomic.Int32
+
+	dataset := make([]searchPoint, 150)
+	for i := range dataset {
+		dataset[i] = searchPoint{postcode: fmt.Sprintf("PC%05d", i), point: geo.Point{Latitude: 51.5, Longitude: -0.1}}
+	}
+
+	srv := newReverseGeocodingServer(t, dataset, &queries)
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+	results, err := c.SearchRadius(context.Background(), geo.Point{Latitude: 51.5, Longitude: -0.1}, 100)
+	assert.ErrorIs(t, err, postcodesio.ErrSearchTruncated)
+	assert.Len(t, results, 100)
+}
+
+func TestSearch_MaxQueries(t *testing.T) {
+	var queries atomic.Int32
+
+	srv := newReverseGeocodingServer(t, nil, &queries)
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	_, err := c.SearchRadius(context.Background(), geo.Point{Latitude: 53, Longitude: -1.5}, 200000)
+	assert.ErrorIs(t, err, postcodesio.ErrSearchTruncated)
+	assert.EqualValues(t, 2000, queries.Load())
+
+	queries.Store(0)
+
+	_, err = c.GeohashPostcodes(context.Background(), "g")
+	assert.ErrorIs(t, err, postcodesio.ErrSearchTruncated)
+	assert.EqualValues(t, 2000, queries.Load())
+}
+
+func TestSearchRadius_NearUKBounds(t *testing.T) {
+	var queries atomic.Int32
+
+	// Lowestoft, near the eastern edge of the UK: tiles of a wide search fall partly or fully outside the UK bounds.
+	center := geo.Point{Latitude: 52.48, Longitude: 1.75}
+	rnd := rand.New(rand.NewSource(1)) //nolint: gosec
+
+	dataset := make([]searchPoint, 2000)
+	for i := range dataset {
+		dataset[i] = searchPoint{
+			postcode: fmt.Sprintf("NR%05d", i),
+			point:    geo.Point{Latitude: 52.2 + rnd.Float64()*0.56, Longitude: 1.3 + rnd.Float64()*0.7},
+		}
+	}
+
+	srv := newReverseGeocodingServer(t, dataset, &queries)
+	defer srv.Close()
+
+	var expected []string
+
+	for _, p := range dataset {
+		if geo.Distance(center, p.point) <= 20000 {
+			expected = append(expected, p.postcode)
+		}
+	}
+
+	c := postcodesio.NewTestClient(srv.URL)
+	results, err := c.SearchRadius(context.Background(), center, 20000)
+	assert.NoError(t, err)
+
+	got := make([]string, len(results))
+	for i, r := range results {
+		got[i] = r.Postcode.Postcode
+	}
+
+	sort.Strings(expected)
+	sort.Strings(got)
+	assert.NotEmpty(t, expected)
+	assert.Equal(t, expected, got)
+}
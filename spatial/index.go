@@ -0,0 +1,134 @@
+// Package spatial provides an in-memory spatial index of postcode centroids, to reverse geocode coordinates offline.
+// Distances are computed on the WGS84 ellipsoid, as the postcodes.io reverse geocoding endpoints do.
+package spatial
+
+import (
+	"math"
+	"sort"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/geo"
+)
+
+// Defaults of the postcodes.io reverse geocoding endpoint, used by ReverseGeocode.
+const (
+	DefaultLimit  = 10
+	DefaultRadius = 100
+)
+
+// chordMargin widens searches by chord distance on the sphere, so that they include every point within the same
+// distance on the ellipsoid, whose radius differs from the sphere by less than 0.4%.
+const chordMargin = 1.01
+
+// Index is an immutable spatial index of postcode centroids, safe for concurrent use.
+type Index struct {
+	postcodes []postcodesio.Postcode
+	points    []vector
+	root      *node
+}
+
+// New builds an index over the postcodes. Postcodes without coordinates are skipped.
+func New(postcodes []postcodesio.Postcode) *Index {
+	ix := &Index{}
+
+	for _, p := range postcodes {
+		lat, lon, ok := p.Location()
+		if !ok {
+			continue
+		}
+
+		ix.postcodes = append(ix.postcodes, p)
+		ix.points = append(ix.points, toVector(lat, lon))
+	}
+
+	indexes := make([]int, len(ix.points))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	ix.root = build(ix.points, indexes, 0)
+
+	return ix
+}
+
+// Len returns the number of postcodes in the index.
+func (ix *Index) Len() int {
+	return len(ix.postcodes)
+}
+
+// Nearest returns the n postcodes nearest to p, nearest first.
+func (ix *Index) Nearest(p geo.Point, n int) []postcodesio.ReversePostcode {
+	if n <= 0 || ix.root == nil {
+		return nil
+	}
+
+	target := toVector(p.Latitude, p.Longitude)
+
+	var maxDist2 float64
+	for _, c := range ix.root.nearest(ix.points, target, n) {
+		maxDist2 = math.Max(maxDist2, c.dist2)
+	}
+
+	// The n nearest by chord distance may differ from the n nearest on the ellipsoid for nearly equidistant
+	// postcodes, so every postcode within a slightly larger distance is ranked.
+	found := ix.root.within(ix.points, target, maxDist2*chordMargin*chordMargin, nil)
+
+	return ix.rank(p, found, math.Inf(1), n)
+}
+
+// Within returns the postcodes within radius metres of p, nearest first, up to limit postcodes if limit is positive.
+func (ix *Index) Within(p geo.Point, radius float64, limit int) []postcodesio.ReversePostcode {
+	if ix.root == nil {
+		return nil
+	}
+
+	chord := 2 * math.Sin(math.Min(radius*chordMargin/geo.EarthRadius, math.Pi)/2) //nolint: gomnd
+	found := ix.root.within(ix.points, toVector(p.Latitude, p.Longitude), chord*chord, nil)
+
+	return ix.rank(p, found, radius, limit)
+}
+
+// ReverseGeocode returns the postcodes nearest to the request coordinates, with the semantics of the postcodes.io
+// reverse geocoding endpoint: up to Limit postcodes, 10 by default, within Radius metres, 100 by default.
+// WideSearch and Filters are ignored, as the index is not subject to the endpoint limits and holds full postcodes.
+func (ix *Index) ReverseGeocode(req postcodesio.ReverseGeocodingRequest) []postcodesio.ReversePostcode {
+	limit, radius := req.Limit, req.Radius
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	if radius <= 0 {
+		radius = DefaultRadius
+	}
+
+	return ix.Within(geo.Point{Latitude: req.Latitude, Longitude: req.Longitude}, radius, limit)
+}
+
+// rank computes the distances of the candidates to p, and returns those within radius, nearest first, up to limit.
+func (ix *Index) rank(p geo.Point, found []candidate, radius float64, limit int) []postcodesio.ReversePostcode {
+	results := make([]postcodesio.ReversePostcode, 0, len(found))
+
+	for _, c := range found {
+		pc := ix.postcodes[c.index]
+		lat, lon, _ := pc.Location()
+
+		d := geo.GeodesicDistance(p, geo.Point{Latitude: lat, Longitude: lon})
+		if d <= radius {
+			results = append(results, postcodesio.ReversePostcode{Postcode: pc, Distance: d})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Distance == results[j].Distance {
+			return results[i].Postcode.Postcode < results[j].Postcode.Postcode
+		}
+
+		return results[i].Distance < results[j].Distance
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
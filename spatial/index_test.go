@@ -0,0 +1,125 @@
+package spatial_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/geo"
+	"github.com/leandrorondon/postcodesio-go/spatial"
+	"github.com/stretchr/testify/assert"
+)
+
+// randomPostcodes returns n postcodes with random coordinates around London.
+func randomPostcodes(n int) []postcodesio.Postcode {
+	rnd := rand.New(rand.NewSource(1)) //nolint: gosec
+
+	postcodes := make([]postcodesio.Postcode, n)
+	for i := range postcodes {
+		postcodes[i] = postcodesio.Postcode{
+			Postcode:  fmt.Sprintf("PC%06d", i),
+			Latitude:  postcodesio.Some(51.3 + rnd.Float64()*0.4),
+			Longitude: postcodesio.Some(-0.5 + rnd.Float64()*0.8),
+		}
+	}
+
+	return postcodes
+}
+
+// bruteForce returns every postcode with its distance to p, nearest first.
+func bruteForce(postcodes []postcodesio.Postcode, p geo.Point) []postcodesio.ReversePostcode {
+	results := make([]postcodesio.ReversePostcode, len(postcodes))
+	for i, pc := range postcodes {
+		lat, lon, _ := pc.Location()
+		results[i] = postcodesio.ReversePostcode{Postcode: pc, Distance: geo.GeodesicDistance(p, geo.Point{Latitude: lat, Longitude: lon})}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Distance < results[j].Distance })
+
+	return results
+}
+
+func TestIndex_Nearest(t *testing.T) {
+	postcodes := randomPostcodes(2000)
+	ix := spatial.New(append(postcodes, postcodesio.Postcode{Postcode: "GY1 1AA"}))
+
+	assert.Equal(t, 2000, ix.Len(), "postcodes without coordinates must be skipped")
+
+	for _, p := range []geo.Point{{Latitude: 51.5, Longitude: -0.1}, {Latitude: 51.31, Longitude: 0.29}, {Latitude: 52, Longitude: 1}} {
+		expected := bruteForce(postcodes, p)[:15]
+
+		assert.Equal(t, expected, ix.Nearest(p, 15))
+	}
+}
+
+func TestIndex_Within(t *testing.T) {
+	postcodes := randomPostcodes(2000)
+	ix := spatial.New(postcodes)
+	p := geo.Point{Latitude: 51.5, Longitude: -0.1}
+
+	var expected []postcodesio.ReversePostcode
+
+	for _, r := range bruteForce(postcodes, p) {
+		if r.Distance <= 2000 {
+			expected = append(expected, r)
+		}
+	}
+
+	assert.NotEmpty(t, expected)
+	assert.Equal(t, expected, ix.Within(p, 2000, 0))
+	assert.Equal(t, expected[:3], ix.Within(p, 2000, 3))
+}
+
+func TestIndex_ReverseGeocode(t *testing.T) {
+	ix := spatial.New([]postcodesio.Postcode{
+		{Postcode: "NW1 6XE", Latitude: postcodesio.Some(51.523659), Longitude: postcodesio.Some(-0.158541)},
+		{Postcode: "SW1A 2AA", Latitude: postcodesio.Some(51.50354), Longitude: postcodesio.Some(-0.127695)},
+	})
+
+	res := ix.ReverseGeocode(postcodesio.ReverseGeocodingRequest{Latitude: 51.5236, Longitude: -0.1585})
+	assert.Len(t, res, 1)
+	assert.Equal(t, "NW1 6XE", res[0].Postcode.Postcode)
+	assert.InDelta(t, 7.15, res[0].Distance, 0.01)
+
+	res = ix.ReverseGeocode(postcodesio.ReverseGeocodingRequest{Latitude: 51.5236, Longitude: -0.1585, Radius: 5000})
+	assert.Len(t, res, 2)
+}
+
+func TestLoadCSV(t *testing.T) {
+	data := "pcd,pcds,oseast1m,osnrth1m,lat,long\n" +
+		"NW1 6XE,NW1 6XE,527850,182134,51.523659,-0.158541\n" +
+		"GY1 1AA,GY1 1AA,,,99.999999,0.000000\n"
+
+	ix, err := spatial.LoadCSV(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ix.Len())
+
+	_, err = spatial.LoadCSV(strings.NewReader("postcode,latitude\nNW1 6XE,51.523659\n"))
+	assert.ErrorIs(t, err, spatial.ErrMissingColumn)
+}
+
+func TestLoadJSON(t *testing.T) {
+	data := `{"postcode":"NW1 6XE","latitude":51.523659,"longitude":-0.158541}
+[{"query":"SW1A2AA","result":{"postcode":"SW1A 2AA","latitude":51.50354,"longitude":-0.127695}},{"query":"XX11XX","result":null}]`
+
+	ix, err := spatial.LoadJSON(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ix.Len())
+
+	res := ix.Nearest(geo.Point{Latitude: 51.5, Longitude: -0.12}, 1)
+	assert.Equal(t, "SW1A 2AA", res[0].Postcode.Postcode)
+}
+
+func BenchmarkIndex_Nearest(b *testing.B) {
+	ix := spatial.New(randomPostcodes(100000))
+	p := geo.Point{Latitude: 51.5, Longitude: -0.1}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ix.Nearest(p, 10)
+	}
+}
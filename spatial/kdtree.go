@@ -0,0 +1,138 @@
+package spatial
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// dimensions of the points of the tree, which are unit vectors on the sphere.
+const dimensions = 3
+
+// vector is a point on the unit sphere. The chord distance between two vectors increases with the great-circle
+// distance between them, which makes it suitable to search the tree.
+type vector [dimensions]float64
+
+func toVector(lat, lon float64) vector {
+	latRad, lonRad := lat*math.Pi/180, lon*math.Pi/180
+	cosLat := math.Cos(latRad)
+
+	return vector{cosLat * math.Cos(lonRad), cosLat * math.Sin(lonRad), math.Sin(latRad)}
+}
+
+func (v vector) dist2(w vector) float64 {
+	var d float64
+
+	for i := range v {
+		d += (v[i] - w[i]) * (v[i] - w[i])
+	}
+
+	return d
+}
+
+// node is a node of a k-d tree, referencing a point by its index.
+type node struct {
+	index       int
+	axis        int
+	left, right *node
+}
+
+// build builds a balanced k-d tree over the given point indexes.
+func build(points []vector, indexes []int, depth int) *node {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	axis := depth % dimensions
+	sort.Slice(indexes, func(i, j int) bool { return points[indexes[i]][axis] < points[indexes[j]][axis] })
+
+	median := len(indexes) / 2 //nolint: gomnd
+
+	return &node{
+		index: indexes[median],
+		axis:  axis,
+		left:  build(points, indexes[:median], depth+1),
+		right: build(points, indexes[median+1:], depth+1),
+	}
+}
+
+// candidate is a point found by a search, with its squared chord distance to the target.
+type candidate struct {
+	index int
+	dist2 float64
+}
+
+// maxHeap is a max-heap of candidates by distance, holding the nearest candidates found so far.
+type maxHeap []candidate
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].dist2 > h[j].dist2 }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) } //nolint: forcetypeassert
+
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return c
+}
+
+// nearest returns the n points nearest to target, in no particular order.
+func (n *node) nearest(points []vector, target vector, k int) []candidate {
+	h := make(maxHeap, 0, k)
+	n.searchNearest(points, target, k, &h)
+
+	return h
+}
+
+func (n *node) searchNearest(points []vector, target vector, k int, h *maxHeap) {
+	if n == nil {
+		return
+	}
+
+	p := points[n.index]
+	if d := p.dist2(target); h.Len() < k {
+		heap.Push(h, candidate{index: n.index, dist2: d})
+	} else if d < (*h)[0].dist2 {
+		(*h)[0] = candidate{index: n.index, dist2: d}
+		heap.Fix(h, 0)
+	}
+
+	diff := target[n.axis] - p[n.axis]
+	near, far := n.left, n.right
+
+	if diff > 0 {
+		near, far = far, near
+	}
+
+	near.searchNearest(points, target, k, h)
+
+	if h.Len() < k || diff*diff < (*h)[0].dist2 {
+		far.searchNearest(points, target, k, h)
+	}
+}
+
+// within returns the points whose squared chord distance to target is at most maxDist2.
+func (n *node) within(points []vector, target vector, maxDist2 float64, found []candidate) []candidate {
+	if n == nil {
+		return found
+	}
+
+	p := points[n.index]
+	if d := p.dist2(target); d <= maxDist2 {
+		found = append(found, candidate{index: n.index, dist2: d})
+	}
+
+	diff := target[n.axis] - p[n.axis]
+
+	if diff <= 0 || diff*diff <= maxDist2 {
+		found = n.left.within(points, target, maxDist2, found)
+	}
+
+	if diff >= 0 || diff*diff <= maxDist2 {
+		found = n.right.within(points, target, maxDist2, found)
+	}
+
+	return found
+}
@@ -0,0 +1,153 @@
+package spatial
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/leandrorondon/postcodesio-go"
+)
+
+// ErrMissingColumn is returned when a CSV dataset lacks a required column.
+var ErrMissingColumn = errors.New("spatial: missing column")
+
+// Accepted CSV column names, in lower case. The ONS Postcode Directory names are included.
+var (
+	postcodeColumns  = []string{"postcode", "pcds", "pcd"}
+	latitudeColumns  = []string{"latitude", "lat"}
+	longitudeColumns = []string{"longitude", "long", "lon"}
+)
+
+// LoadCSV builds an index from a CSV dataset with a header row, such as the ONS Postcode Directory. The postcode,
+// latitude and longitude columns are required, and are found by name: postcode, pcds or pcd; latitude or lat;
+// longitude, long or lon. Rows without valid coordinates are skipped.
+func LoadCSV(r io.Reader) (*Index, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	pcCol, err := findColumn(columns, postcodeColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	latCol, err := findColumn(columns, latitudeColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	lonCol, err := findColumn(columns, longitudeColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	var postcodes []postcodesio.Postcode
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		lat, latErr := strconv.ParseFloat(record[latCol], 64)
+		lon, lonErr := strconv.ParseFloat(record[lonCol], 64)
+
+		// The ONS Postcode Directory uses a latitude of 99.999999 for postcodes without a grid reference.
+		if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			continue
+		}
+
+		postcodes = append(postcodes, postcodesio.Postcode{
+			Postcode:  record[pcCol],
+			Latitude:  postcodesio.Some(lat),
+			Longitude: postcodesio.Some(lon),
+		})
+	}
+
+	return New(postcodes), nil
+}
+
+func findColumn(columns map[string]int, names []string) (int, error) {
+	for _, name := range names {
+		if i, ok := columns[name]; ok {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrMissingColumn, strings.Join(names, " or "))
+}
+
+// LoadJSON builds an index from a stream of JSON values, each being a postcode or a bulk lookup result, or an array
+// of them. This reads JSON lines exports as well as the files written by postcodesio.DirSink.
+func LoadJSON(r io.Reader) (*Index, error) {
+	dec := json.NewDecoder(r)
+
+	var postcodes []postcodesio.Postcode
+
+	for {
+		var raw json.RawMessage
+
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		items := []json.RawMessage{raw}
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, item := range items {
+			p, err := decodePostcode(item)
+			if err != nil {
+				return nil, err
+			}
+
+			postcodes = append(postcodes, p)
+		}
+	}
+
+	return New(postcodes), nil
+}
+
+// decodePostcode decodes a postcode, or the postcode of a bulk lookup result.
+func decodePostcode(item json.RawMessage) (postcodesio.Postcode, error) {
+	var p postcodesio.Postcode
+	if err := json.Unmarshal(item, &p); err != nil {
+		return p, err
+	}
+
+	if result, ok := p.Extra["result"]; ok {
+		var bulk postcodesio.Postcode
+
+		err := json.Unmarshal(result, &bulk)
+
+		return bulk, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,210 @@
+package postcodesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxBulkSize        = 100
+	defaultConcurrency = 4
+	defaultBatchLinger = 100 * time.Millisecond
+)
+
+// LookupResult is the result of the lookup of a postcode by LookupStream.
+// Result is nil if the postcode was not found. Err is set if the bulk lookup of the postcode failed.
+type LookupResult struct {
+	Query  string
+	Result *Postcode
+	Err    error
+}
+
+// StreamOption describes the type for functional options used with LookupStream.
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	batchSize   int
+	concurrency int
+	linger      time.Duration
+	requestOpts []RequestOption
+}
+
+// WithBatchSize is the option to set the number of postcodes per bulk lookup, up to and by default 100.
+func WithBatchSize(size int) StreamOption {
+	return func(c *streamConfig) {
+		if size > 0 && size <= maxBulkSize {
+			c.batchSize = size
+		}
+	}
+}
+
+// WithConcurrency is the option to set the number of bulk lookups in flight, defaults to 4.
+func WithConcurrency(concurrency int) StreamOption {
+	return func(c *streamConfig) {
+		if concurrency > 0 {
+			c.concurrency = concurrency
+		}
+	}
+}
+
+// WithBatchLinger is the option to set for how long an incomplete batch waits for more postcodes before being sent,
+// defaults to 100 milliseconds.
+func WithBatchLinger(linger time.Duration) StreamOption {
+	return func(c *streamConfig) {
+		c.linger = linger
+	}
+}
+
+// WithStreamRequestOptions is the option to set the request options used on every bulk lookup.
+func WithStreamRequestOptions(opts ...RequestOption) StreamOption {
+	return func(c *streamConfig) {
+		c.requestOpts = opts
+	}
+}
+
+// LookupStream looks up the postcodes received from in, batching them into concurrent bulk lookups, and sends the
+// results to the returned channel as they arrive, not necessarily in the input order. Reading from in stops while
+// the results are not consumed, so memory stays bounded. The returned channel is closed once in is closed and every
+// result has been sent, or when ctx is done. Filters set with WithFilter always include the postcode.
+func (c *Client) LookupStream(ctx context.Context, in <-chan string, opts ...StreamOption) <-chan LookupResult {
+	cfg := &streamConfig{
+		batchSize:   maxBulkSize,
+		concurrency: defaultConcurrency,
+		linger:      defaultBatchLinger,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	batches := make(chan []string)
+	out := make(chan LookupResult)
+
+	go batch(ctx, in, batches, cfg.batchSize, cfg.linger)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < cfg.concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for postcodes := range batches {
+				if !c.lookupBatch(ctx, postcodes, out, cfg.requestOpts) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// batch groups the postcodes received from in into batches of up to size postcodes. An incomplete batch is sent after
+// waiting linger for more postcodes.
+func batch(ctx context.Context, in <-chan string, batches chan<- []string, size int, linger time.Duration) {
+	defer close(batches)
+
+	var (
+		current []string
+		timeout <-chan time.Time
+	)
+
+	flush := func() bool {
+		if len(current) == 0 {
+			return true
+		}
+
+		select {
+		case batches <- current:
+			current, timeout = nil, nil
+
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		select {
+		case postcode, ok := <-in:
+			if !ok {
+				flush()
+
+				return
+			}
+
+			if len(current) == 0 {
+				timeout = time.After(linger)
+			}
+
+			current = append(current, postcode)
+
+			if len(current) == size && !flush() {
+				return
+			}
+		case <-timeout:
+			if !flush() {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// lookupBatch looks up a batch of postcodes and sends the results to out, reporting false if ctx is done.
+func (c *Client) lookupBatch(ctx context.Context, postcodes []string, out chan<- LookupResult, opts []RequestOption) bool {
+	results := make([]LookupResult, len(postcodes))
+
+	// Blank postcodes are invalid in a bulk lookup, and are reported as not found without being sent.
+	var queried []int
+
+	for i, postcode := range postcodes {
+		results[i] = LookupResult{Query: postcode}
+
+		if strings.TrimSpace(postcode) != "" {
+			queried = append(queried, i)
+		}
+	}
+
+	if len(queried) > 0 {
+		request := BulkPostCodeLookupRequest{Filters: requiredFilters(opts, FieldPostcode)}
+		for _, i := range queried {
+			request.Postcodes = append(request.Postcodes, postcodes[i])
+		}
+
+		res, err := c.BulkPostcodeLookup(ctx, request, opts...)
+		if err == nil && res.Status != http.StatusOK {
+			err = fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.Status)
+		}
+
+		for j, i := range queried {
+			results[i].Err = err
+
+			if err == nil && j < len(res.Result) && res.Result[j].Result.Postcode != "" {
+				results[i].Result = &res.Result[j].Result
+			}
+		}
+	}
+
+	for _, r := range results {
+		select {
+		case out <- r:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,231 @@
+package postcodesio_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newBulkLookupServer returns a server answering bulk lookups, where postcodes starting with "XX" are not found.
+func newBulkLookupServer(t *testing.T, batchSizes *[]int, mu *sync.Mutex) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req postcodesio.BulkPostCodeLookupRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		mu.Lock()
+		*batchSizes = append(*batchSizes, len(req.Postcodes))
+		mu.Unlock()
+
+		results := make([]string, len(req.Postcodes))
+		for i, p := range req.Postcodes {
+			if strings.HasPrefix(p, "XX") {
+				results[i] = fmt.Sprintf(`{"query":%q,"result":null}`, p)
+			} else {
+				results[i] = fmt.Sprintf(`{"query":%q,"result":{"postcode":%q}}`, p, p)
+			}
+		}
+
+		fmt.Fprintf(w, `{"status":200,"result":[%s]}`, strings.Join(results, ","))
+	}))
+}
+
+// newFilteringLookupServer returns a server answering bulk lookups of the given postcodes, by normalised postcode,
+// with only the attributes in the filter query parameter, as the API does.
+func newFilteringLookupServer(t *testing.T, postcodes map[string]map[string]any) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req postcodesio.BulkPostCodeLookupRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		filter := r.URL.Query().Get("filter")
+		results := make([]map[string]any, len(req.Postcodes))
+
+		for i, p := range req.Postcodes {
+			results[i] = map[string]any{"query": p, "result": nil}
+
+			attrs, ok := postcodes[postcodesio.NormalizePostcode(p)]
+			if !ok {
+				continue
+			}
+
+			result := make(map[string]any)
+
+			for name, value := range attrs {
+				if filter == "" || strings.Contains(","+filter+",", ","+name+",") {
+					result[name] = value
+				}
+			}
+
+			results[i]["result"] = result
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"status": 200, "result": results}))
+	}))
+}
+
+func TestLookupStream(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		batchSizes []int
+	)
+
+	srv := newBulkLookupServer(t, &batchSizes, &mu)
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	in := make(chan string)
+	go func() {
+		defer close(in)
+
+		for i := 0; i < 250; i++ {
+			in <- fmt.Sprintf("AB%d 1CD", i)
+		}
+
+		in <- "XX1 1XX"
+	}()
+
+	found := make(map[string]bool)
+	notFound := 0
+
+	for r := range c.LookupStream(context.Background(), in, postcodesio.WithConcurrency(2)) {
+		assert.NoError(t, r.Err)
+
+		if r.Result == nil {
+			notFound++
+
+			continue
+		}
+
+		assert.Equal(t, r.Query, r.Result.Postcode)
+		found[r.Query] = true
+	}
+
+	assert.Len(t, found, 250)
+	assert.Equal(t, 1, notFound)
+
+	total := 0
+	for _, size := range batchSizes {
+		assert.LessOrEqual(t, size, 100)
+		total += size
+	}
+
+	assert.Equal(t, 251, total)
+}
+
+func TestLookupStream_Linger(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		batchSizes []int
+	)
+
+	srv := newBulkLookupServer(t, &batchSizes, &mu)
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	in := make(chan string)
+	defer close(in)
+
+	results := c.LookupStream(context.Background(), in, postcodesio.WithBatchLinger(10*time.Millisecond))
+	in <- "NW1 6XE"
+
+	select {
+	case r := <-results:
+		assert.Equal(t, "NW1 6XE", r.Query)
+	case <-time.After(time.Second):
+		t.Fatal("incomplete batch was not sent after the linger period")
+	}
+}
+
+func TestLookupStream_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"status":400,"error":"Invalid JSON submitted"}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	in := make(chan string, 2)
+	in <- "NW1 6XE"
+	in <- "SW1A 2AA"
+	close(in)
+
+	count := 0
+	for r := range c.LookupStream(context.Background(), in) {
+		assert.ErrorIs(t, r.Err, postcodesio.ErrUnexpectedStatus)
+		count++
+	}
+
+	assert.Equal(t, 2, count)
+}
+
+func TestLookupStream_Cancel(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		batchSizes []int
+	)
+
+	srv := newBulkLookupServer(t, &batchSizes, &mu)
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string)
+
+	results := c.LookupStream(ctx, in)
+	cancel()
+
+	select {
+	case _, ok := <-results:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("results channel was not closed after cancellation")
+	}
+}
+
+func TestLookupStream_Filter(t *testing.T) {
+	srv := newFilteringLookupServer(t, map[string]map[string]any{
+		"NW16XE": {"postcode": "NW1 6XE", "country": "England"},
+	})
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	in := make(chan string, 3)
+	in <- "NW1 6XE"
+	in <- "XX1 1XX"
+	in <- " "
+	close(in)
+
+	results := make(map[string]*postcodesio.Postcode)
+
+	opts := postcodesio.WithStreamRequestOptions(postcodesio.WithFilter(postcodesio.FieldCountry))
+	for r := range c.LookupStream(context.Background(), in, opts) {
+		assert.NoError(t, r.Err)
+		results[r.Query] = r.Result
+	}
+
+	if assert.NotNil(t, results["NW1 6XE"], "a postcode found must not be reported as not found with filters") {
+		assert.Equal(t, "England", results["NW1 6XE"].Country)
+	}
+
+	for _, query := range []string{"XX1 1XX", " "} {
+		assert.Contains(t, results, query)
+		assert.Nil(t, results[query])
+	}
+}
@@ -0,0 +1,193 @@
+package postcodesio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+const (
+	// costConfusion is the cost of replacing a character with one it is commonly mistaken for, e.g. O and 0.
+	costConfusion = 1
+	// costEdit is the cost of a keyboard slip, a transposition of adjacent characters or an extra character.
+	costEdit = 2
+	// maxConfusions is the maximum number of confused characters corrected in a single candidate.
+	maxConfusions = 2
+	// maxSuggestCandidates bounds the number of candidates checked, so a suggestion costs at most a few bulk lookups.
+	maxSuggestCandidates = 3 * maxBulkSize
+	// maxSuggestInputLen is the maximum length of a normalised input: the longest postcode with an extra character.
+	// The number of candidates grows quickly with the input length.
+	maxSuggestInputLen = 8
+)
+
+// confusions maps characters to those they are commonly mistaken for when typed, read or scanned.
+var confusions = map[byte]string{
+	'0': "OQD", 'O': "0QD", 'Q': "O0", 'D': "0O",
+	'1': "IL", 'I': "1L", 'L': "1I",
+	'2': "Z", 'Z': "2",
+	'5': "S", 'S': "5",
+	'6': "G", 'G': "6",
+	'8': "B", 'B': "8",
+	'U': "V", 'V': "U",
+}
+
+// keyboardRows is the QWERTY layout used to find the keys next to a mistyped character.
+var keyboardRows = []string{"1234567890", "QWERTYUIOP", "ASDFGHJKL", "ZXCVBNM"}
+
+// Suggestion is an existing postcode suggested as a correction of a mistyped one.
+// Cost measures how far the suggestion is from the input, 0 being the input itself.
+type Suggestion struct {
+	Postcode string
+	Cost     int
+	Result   Postcode
+}
+
+// Suggest returns existing postcodes the input (case, space insensitive) was likely meant to be, cheapest first.
+// Candidates are generated from common character confusions (O and 0, I and 1, ...), keyboard slips, transposed
+// and extra characters, filtered by the UK postcode format rules and checked with BulkPostcodeLookup.
+// If the input is an existing postcode, it is the first suggestion with cost 0. Filters set with WithFilter always
+// include the postcode. Inputs longer than a postcode with an extra character fail with ErrInvalidPostcode.
+func (c *Client) Suggest(ctx context.Context, input string, opts ...RequestOption) ([]Suggestion, error) {
+	normalized := NormalizePostcode(input)
+
+	switch {
+	case normalized == "":
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPostcode, input)
+	case len(normalized) > maxSuggestInputLen:
+		return nil, fmt.Errorf("%w: %d characters, at most %d", ErrInvalidPostcode, len(normalized), maxSuggestInputLen)
+	}
+
+	candidates := suggestCandidates(normalized)
+
+	var suggestions []Suggestion
+
+	for start := 0; start < len(candidates); start += maxBulkSize {
+		chunk := candidates[start:min(start+maxBulkSize, len(candidates))]
+
+		postcodes := make([]string, len(chunk))
+		for i, candidate := range chunk {
+			postcodes[i] = candidate.Postcode
+		}
+
+		request := BulkPostCodeLookupRequest{Postcodes: postcodes, Filters: requiredFilters(opts, FieldPostcode)}
+
+		res, err := c.BulkPostcodeLookup(ctx, request, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.Status != http.StatusOK {
+			return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatus, res.Status)
+		}
+
+		for i := range res.Result {
+			if i < len(chunk) && res.Result[i].Result.Postcode != "" {
+				chunk[i].Result = res.Result[i].Result
+				suggestions = append(suggestions, chunk[i])
+			}
+		}
+	}
+
+	return suggestions, nil
+}
+
+// suggestCandidates returns the well-formed corrections of a normalised postcode, cheapest first.
+func suggestCandidates(normalized string) []Suggestion {
+	costs := map[string]int{}
+
+	add := func(candidate string, cost int) {
+		if prev, ok := costs[candidate]; !ok || cost < prev {
+			costs[candidate] = cost
+		}
+	}
+
+	add(normalized, 0)
+	addConfusions(normalized, 0, 0, add)
+
+	s := []byte(normalized)
+
+	for i := range s {
+		for _, r := range keyboardNeighbours(s[i]) {
+			add(replaceAt(s, i, r), costEdit)
+		}
+
+		add(normalized[:i]+normalized[i+1:], costEdit)
+
+		if i+1 < len(s) && s[i] != s[i+1] {
+			t := []byte(normalized)
+			t[i], t[i+1] = t[i+1], t[i]
+			add(string(t), costEdit)
+		}
+	}
+
+	candidates := make([]Suggestion, 0, len(costs))
+
+	for candidate, cost := range costs {
+		if parts, err := ParsePostcode(candidate); err == nil {
+			candidates = append(candidates, Suggestion{Postcode: parts.String(), Cost: cost})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Cost != candidates[j].Cost {
+			return candidates[i].Cost < candidates[j].Cost
+		}
+
+		return candidates[i].Postcode < candidates[j].Postcode
+	})
+
+	if len(candidates) > maxSuggestCandidates {
+		candidates = candidates[:maxSuggestCandidates]
+	}
+
+	return candidates
+}
+
+// addConfusions adds the candidates correcting up to maxConfusions confused characters from position from onwards.
+func addConfusions(s string, from, corrected int, add func(string, int)) {
+	if corrected == maxConfusions {
+		return
+	}
+
+	for i := from; i < len(s); i++ {
+		for _, r := range []byte(confusions[s[i]]) {
+			candidate := replaceAt([]byte(s), i, r)
+			add(candidate, (corrected+1)*costConfusion)
+			addConfusions(candidate, i+1, corrected+1, add)
+		}
+	}
+}
+
+// keyboardNeighbours returns the characters on the keys left and right of c.
+func keyboardNeighbours(c byte) []byte {
+	for _, row := range keyboardRows {
+		for i := 0; i < len(row); i++ {
+			if row[i] != c {
+				continue
+			}
+
+			var neighbours []byte
+			if i > 0 {
+				neighbours = append(neighbours, row[i-1])
+			}
+
+			if i+1 < len(row) {
+				neighbours = append(neighbours, row[i+1])
+			}
+
+			return neighbours
+		}
+	}
+
+	return nil
+}
+
+// replaceAt returns s with the character at position i replaced by c.
+func replaceAt(s []byte, i int, c byte) string {
+	t := make([]byte, len(s))
+	copy(t, s)
+	t[i] = c
+
+	return string(t)
+}
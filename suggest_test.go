@@ -0,0 +1,118 @@
+package postcodesio_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSuggestServer returns a server answering bulk lookups, where only the given postcodes exist.
+func newSuggestServer(t *testing.T, requests *int, existing ...string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req postcodesio.BulkPostCodeLookupRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.LessOrEqual(t, len(req.Postcodes), 100)
+
+		*requests++
+
+		results := make([]string, len(req.Postcodes))
+		for i, p := range req.Postcodes {
+			results[i] = fmt.Sprintf(`{"query":%q,"result":null}`, p)
+
+			for _, e := range existing {
+				if p == e {
+					results[i] = fmt.Sprintf(`{"query":%q,"result":{"postcode":%q}}`, p, p)
+				}
+			}
+		}
+
+		fmt.Fprintf(w, `{"status":200,"result":[%s]}`, strings.Join(results, ","))
+	}))
+}
+
+func TestSuggest(t *testing.T) {
+	var requests int
+
+	srv := newSuggestServer(t, &requests, "SW1A 2AA", "SW1A 1AA", "NW1 6XE", "SO1 0AA")
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	tests := []struct {
+		input    string
+		expected []postcodesio.Suggestion
+	}{
+		{"SWIA 2AA", []postcodesio.Suggestion{{Postcode: "SW1A 2AA", Cost: 1}}},
+		{"5WIA 2AA", []postcodesio.Suggestion{{Postcode: "SW1A 2AA", Cost: 2}}},
+		{"NW1 X6E", []postcodesio.Suggestion{{Postcode: "NW1 6XE", Cost: 2}}},
+		{"NW1 6XEE", []postcodesio.Suggestion{{Postcode: "NW1 6XE", Cost: 2}}},
+		{"SW1A 3AA", []postcodesio.Suggestion{{Postcode: "SW1A 2AA", Cost: 2}}},
+		{"sw1a2aa", []postcodesio.Suggestion{{Postcode: "SW1A 2AA", Cost: 0}, {Postcode: "SW1A 1AA", Cost: 2}}},
+		{"ZZ99 9ZZ", nil},
+	}
+
+	for _, tt := range tests {
+		suggestions, err := c.Suggest(context.Background(), tt.input)
+		assert.NoError(t, err, tt.input)
+
+		var got []postcodesio.Suggestion
+
+		for _, s := range suggestions {
+			assert.Equal(t, s.Postcode, s.Result.Postcode)
+			got = append(got, postcodesio.Suggestion{Postcode: s.Postcode, Cost: s.Cost})
+		}
+
+		assert.Equal(t, tt.expected, got, tt.input)
+	}
+
+	requests = 0
+	_, err := c.Suggest(context.Background(), "  ")
+	assert.ErrorIs(t, err, postcodesio.ErrInvalidPostcode)
+	assert.Zero(t, requests)
+
+	for _, input := range []string{"SW1A 2AAXX", strings.Repeat("SW1A 2AA", 50)} {
+		_, err = c.Suggest(context.Background(), input)
+		assert.ErrorIs(t, err, postcodesio.ErrInvalidPostcode)
+		assert.NotContains(t, err.Error(), input, "long inputs must not be copied to the error")
+		assert.Zero(t, requests)
+	}
+}
+
+func TestSuggest_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"status":500,"error":"boom"}`)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	_, err := c.Suggest(context.Background(), "SW1A 2AA")
+	assert.Error(t, err)
+}
+
+func TestSuggest_Filter(t *testing.T) {
+	srv := newFilteringLookupServer(t, map[string]map[string]any{
+		"SW1A2AA": {"postcode": "SW1A 2AA", "country": "England"},
+	})
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+
+	suggestions, err := c.Suggest(context.Background(), "SWIA 2AA", postcodesio.WithFilter(postcodesio.FieldCountry))
+	assert.NoError(t, err)
+
+	if assert.Len(t, suggestions, 1) {
+		assert.Equal(t, "SW1A 2AA", suggestions[0].Postcode)
+		assert.Equal(t, "England", suggestions[0].Result.Country)
+	}
+}
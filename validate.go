@@ -0,0 +1,186 @@
+package postcodesio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leandrorondon/postcodesio-go/geo"
+)
+
+// maxWideSearchRadius is the maximum radius in metres of a reverse geocoding query with wide search.
+const maxWideSearchRadius = 20000
+
+// ukBounds covers the postcodes known to the API, from the Channel Islands to Shetland.
+var ukBounds = geo.BoundingBox{
+	Min: geo.Point{Latitude: 49, Longitude: -9},
+	Max: geo.Point{Latitude: 61, Longitude: 2},
+}
+
+// Violation is a constraint of the API that a request does not satisfy.
+type Violation struct {
+	Field   string
+	Message string
+
+	// err is the sentinel error matched by the ValidationError in addition to ErrInvalidRequest, if any.
+	err error
+}
+
+// ValidationError lists the violations of a request, which is not sent. It matches ErrInvalidRequest, and
+// ErrUnknownField if a filter is not a known field.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Field + ": " + v.Message
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidRequest, strings.Join(messages, "; "))
+}
+
+// Unwrap returns ErrInvalidRequest, followed by the sentinel errors of the violations.
+func (e *ValidationError) Unwrap() []error {
+	errs := []error{ErrInvalidRequest}
+
+	for _, v := range e.Violations {
+		if v.err != nil && !slices.Contains(errs, v.err) {
+			errs = append(errs, v.err)
+		}
+	}
+
+	return errs
+}
+
+// Validate checks the request against the constraints of the API: between 1 and 100 postcodes, none of them blank,
+// and known filter fields. It returns a *ValidationError listing all the violations, or nil.
+func (r BulkPostCodeLookupRequest) Validate() error {
+	var v validator
+
+	v.count("postcodes", len(r.Postcodes))
+
+	for i, postcode := range r.Postcodes {
+		if strings.TrimSpace(postcode) == "" {
+			v.add(fmt.Sprintf("postcodes[%d]", i), "must not be blank")
+		}
+	}
+
+	v.filters(r.Filters)
+
+	return v.err()
+}
+
+// Validate checks the request against the constraints of the API: coordinates within the UK, a limit up to 100,
+// a radius up to 2000 metres, or 20000 metres with wide search, and known filter fields.
+// It returns a *ValidationError listing all the violations, or nil.
+func (r ReverseGeocodingRequest) Validate() error {
+	var v validator
+
+	v.geolocation("", Geolocation{
+		Latitude:   r.Latitude,
+		Longitude:  r.Longitude,
+		Limit:      r.Limit,
+		Radius:     r.Radius,
+		WideSearch: r.WideSearch,
+	})
+	v.filters(r.Filters)
+
+	return v.err()
+}
+
+// Validate checks the geolocation against the constraints of the API, as ReverseGeocodingRequest.Validate.
+// It returns a *ValidationError listing all the violations, or nil.
+func (g Geolocation) Validate() error {
+	var v validator
+
+	v.geolocation("", g)
+
+	return v.err()
+}
+
+// Validate checks the request against the constraints of the API: between 1 and 100 geolocations, each of them valid,
+// and known filter fields. It returns a *ValidationError listing all the violations, or nil.
+func (r BulkReverseGeocodingRequest) Validate() error {
+	var v validator
+
+	v.count("geolocations", len(r.Geolocations))
+
+	for i, g := range r.Geolocations {
+		v.geolocation(fmt.Sprintf("geolocations[%d].", i), g)
+	}
+
+	v.filters(r.Filters)
+
+	return v.err()
+}
+
+// validator collects the violations of a request.
+type validator struct {
+	violations []Violation
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns a *ValidationError with the violations, or nil if there are none.
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Violations: v.violations}
+}
+
+// count checks the number of items of a bulk request.
+func (v *validator) count(field string, n int) {
+	switch {
+	case n == 0:
+		v.add(field, "must not be empty")
+	case n > maxBulkSize:
+		v.add(field, "must not have more than %d items, got %d", maxBulkSize, n)
+	}
+}
+
+// filters checks that the filters are known Postcode attributes.
+func (v *validator) filters(filters []Field) {
+	for i, f := range filters {
+		if !f.Valid() {
+			v.violations = append(v.violations, Violation{
+				Field:   fmt.Sprintf("filters[%d]", i),
+				Message: fmt.Sprintf("unknown field %q", f),
+				err:     ErrUnknownField,
+			})
+		}
+	}
+}
+
+// geolocation checks a reverse geocoding query, prefixing its field names.
+func (v *validator) geolocation(prefix string, g Geolocation) {
+	// Negated comparisons also reject NaN.
+	if !(g.Latitude >= ukBounds.Min.Latitude && g.Latitude <= ukBounds.Max.Latitude) {
+		v.add(prefix+"latitude", "must be within the UK, between %g and %g, got %g",
+			ukBounds.Min.Latitude, ukBounds.Max.Latitude, g.Latitude)
+	}
+
+	if !(g.Longitude >= ukBounds.Min.Longitude && g.Longitude <= ukBounds.Max.Longitude) {
+		v.add(prefix+"longitude", "must be within the UK, between %g and %g, got %g",
+			ukBounds.Min.Longitude, ukBounds.Max.Longitude, g.Longitude)
+	}
+
+	if g.Limit < 0 || g.Limit > maxReverseLimit {
+		v.add(prefix+"limit", "must be between 0 and %d, got %d", maxReverseLimit, g.Limit)
+	}
+
+	maxRadius := float64(maxReverseRadius)
+	if g.WideSearch {
+		maxRadius = maxWideSearchRadius
+	}
+
+	if !(g.Radius >= 0 && g.Radius <= maxRadius) {
+		v.add(prefix+"radius", "must be between 0 and %g metres, got %g", maxRadius, g.Radius)
+	}
+}
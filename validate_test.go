@@ -0,0 +1,150 @@
+package postcodesio_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = "NW1 6XE"
+	}
+
+	nw16xe := postcodesio.Geolocation{Latitude: 51.523659, Longitude: -0.158541}
+
+	tests := []struct {
+		name     string
+		request  interface{ Validate() error }
+		expected []string
+	}{
+		{
+			name:    "bulk lookup",
+			request: postcodesio.BulkPostCodeLookupRequest{Postcodes: []string{"NW1 6XE"}},
+		},
+		{
+			name:     "bulk lookup without postcodes",
+			request:  postcodesio.BulkPostCodeLookupRequest{},
+			expected: []string{"postcodes"},
+		},
+		{
+			name:     "bulk lookup with too many postcodes",
+			request:  postcodesio.BulkPostCodeLookupRequest{Postcodes: tooMany},
+			expected: []string{"postcodes"},
+		},
+		{
+			name: "bulk lookup with blank postcodes and unknown filters",
+			request: postcodesio.BulkPostCodeLookupRequest{
+				Postcodes: []string{"NW1 6XE", " ", ""},
+				Filters:   []postcodesio.Field{postcodesio.FieldPostcode, "postcod"},
+			},
+			expected: []string{"postcodes[1]", "postcodes[2]", "filters[1]"},
+		},
+		{
+			name:    "reverse geocoding",
+			request: postcodesio.ReverseGeocodingRequest{Latitude: 51.523659, Longitude: -0.158541, Limit: 100, Radius: 2000},
+		},
+		{
+			name:    "reverse geocoding with wide search",
+			request: postcodesio.ReverseGeocodingRequest{Latitude: 51.523659, Longitude: -0.158541, Radius: 20000, WideSearch: true},
+		},
+		{
+			name:     "reverse geocoding with every value invalid",
+			request:  postcodesio.ReverseGeocodingRequest{Latitude: 40.7, Longitude: math.NaN(), Limit: 101, Radius: 2001},
+			expected: []string{"latitude", "longitude", "limit", "radius"},
+		},
+		{
+			name:     "reverse geocoding with negative values",
+			request:  postcodesio.ReverseGeocodingRequest{Latitude: 51.5, Longitude: -0.1, Limit: -1, Radius: -1},
+			expected: []string{"limit", "radius"},
+		},
+		{
+			name:     "reverse geocoding with wide search radius too large",
+			request:  postcodesio.ReverseGeocodingRequest{Latitude: 51.5, Longitude: -0.1, Radius: 20001, WideSearch: true},
+			expected: []string{"radius"},
+		},
+		{
+			name:     "reverse geocoding with unknown filters",
+			request:  postcodesio.ReverseGeocodingRequest{Latitude: 91, Longitude: -0.1, Filters: []postcodesio.Field{"lat"}},
+			expected: []string{"latitude", "filters[0]"},
+		},
+		{
+			name:     "geolocation",
+			request:  postcodesio.Geolocation{},
+			expected: []string{"latitude"},
+		},
+		{
+			name: "bulk reverse geocoding",
+			request: postcodesio.BulkReverseGeocodingRequest{Geolocations: []postcodesio.Geolocation{
+				nw16xe, {Latitude: 51.5, Longitude: 3}, nw16xe, {Latitude: 51.5, Longitude: -0.1, Limit: 500},
+			}, Filters: []postcodesio.Field{"x"}},
+			expected: []string{"geolocations[1].longitude", "geolocations[3].limit", "filters[0]"},
+		},
+		{
+			name:     "bulk reverse geocoding without geolocations",
+			request:  postcodesio.BulkReverseGeocodingRequest{},
+			expected: []string{"geolocations"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.request.Validate()
+			if test.expected == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, postcodesio.ErrInvalidRequest)
+
+			var validationErr *postcodesio.ValidationError
+			assert.True(t, errors.As(err, &validationErr))
+
+			fields := make([]string, len(validationErr.Violations))
+			for i, v := range validationErr.Violations {
+				fields[i] = v.Field
+				assert.Contains(t, err.Error(), v.Field+": "+v.Message)
+			}
+
+			assert.Equal(t, test.expected, fields)
+
+			unknownField := strings.HasPrefix(fields[len(fields)-1], "filters[")
+			assert.Equal(t, unknownField, errors.Is(err, postcodesio.ErrUnknownField))
+		})
+	}
+}
+
+func TestValidate_BeforeSending(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	}))
+	defer srv.Close()
+
+	c := postcodesio.NewTestClient(srv.URL)
+	ctx := context.Background()
+
+	_, err := c.BulkPostcodeLookup(ctx, postcodesio.BulkPostCodeLookupRequest{})
+	assert.ErrorIs(t, err, postcodesio.ErrInvalidRequest)
+
+	_, err = c.ReverseGeocoding(ctx, postcodesio.ReverseGeocodingRequest{Latitude: 51.5, Longitude: -0.1, Limit: 101})
+	assert.ErrorIs(t, err, postcodesio.ErrInvalidRequest)
+	assert.True(t, strings.HasPrefix(err.Error(), "invalid request: limit: "), err.Error())
+
+	_, err = c.BulkReverseGeocoding(ctx, postcodesio.BulkReverseGeocodingRequest{
+		Geolocations: []postcodesio.Geolocation{{Latitude: 51.5, Longitude: -0.1, Radius: 3000}},
+	})
+	assert.ErrorIs(t, err, postcodesio.ErrInvalidRequest)
+
+	err = c.BulkPostcodeLookupEach(ctx, postcodesio.BulkPostCodeLookupRequest{},
+		func(postcodesio.BulkPostcodeLookupQueryResponse) error { return nil })
+	assert.ErrorIs(t, err, postcodesio.ErrInvalidRequest)
+}
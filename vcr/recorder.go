@@ -0,0 +1,255 @@
+// Package vcr records HTTP interactions with postcodes.io to cassette files and replays them, so that code using
+// postcodesio.Client can be tested deterministically and offline. A Recorder is an http.RoundTripper meant to be
+// used with postcodesio.WithTransport.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrNotRecorded is returned in strict mode when a request has no matching interaction in the cassette.
+var ErrNotRecorded = errors.New("vcr: request not recorded")
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay serves requests from the cassette. Unrecorded requests are sent and recorded, unless the Recorder
+	// is strict.
+	ModeReplay Mode = iota
+	// ModeRecord sends every request and records it, replacing the interactions in the cassette.
+	ModeRecord
+)
+
+// Matcher reports whether a request, whose body has already been read, matches a recorded request.
+type Matcher func(r *http.Request, body []byte, recorded Request) bool
+
+// Option describes the type for functional options used with New.
+type Option func(*Recorder)
+
+// WithMode is the option to set the mode of the Recorder, defaults to ModeReplay.
+func WithMode(mode Mode) Option {
+	return func(r *Recorder) {
+		r.mode = mode
+	}
+}
+
+// WithStrict is the option to make a replaying Recorder fail requests that are not recorded with ErrNotRecorded,
+// instead of sending them.
+func WithStrict() Option {
+	return func(r *Recorder) {
+		r.strict = true
+	}
+}
+
+// WithTransport is the option to set the transport used to send requests, defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithMatcher is the option to set how requests are matched to recorded ones, defaults to DefaultMatcher.
+func WithMatcher(matcher Matcher) Option {
+	return func(r *Recorder) {
+		r.matcher = matcher
+	}
+}
+
+// Recorder is an http.RoundTripper recording interactions to, or replaying them from, a cassette file.
+// Call Save once done to write the recorded interactions.
+type Recorder struct {
+	path      string
+	mode      Mode
+	strict    bool
+	transport http.RoundTripper
+	matcher   Matcher
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+	changed  bool
+}
+
+// New returns a Recorder using the cassette file at path, which does not need to exist yet.
+func New(path string, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      ModeReplay,
+		transport: http.DefaultTransport,
+		matcher:   DefaultMatcher,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.cassette = &Cassette{}
+
+	if r.mode == ModeReplay {
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+
+		r.cassette = c
+	}
+
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		if i, ok := r.find(req, body); ok {
+			return r.cassette.Interactions[i].Response.response(req), nil
+		}
+
+		if r.strict {
+			return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL)
+		}
+	}
+
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := Response{Status: res.StatusCode, Header: res.Header.Clone(), Body: string(b)}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String(), Body: string(body)},
+		Response: recorded,
+	})
+	r.used = append(r.used, true)
+	r.changed = true
+	r.mu.Unlock()
+
+	return recorded.response(req), nil
+}
+
+// Save writes the cassette file if any interaction was recorded.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.changed {
+		return nil
+	}
+
+	if err := r.cassette.Save(r.path); err != nil {
+		return err
+	}
+
+	r.changed = false
+
+	return nil
+}
+
+// find returns the first unused interaction matching the request, or else the last used one, so that repeated
+// requests are replayed in the recorded order.
+func (r *Recorder) find(req *http.Request, body []byte) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+
+	for i, interaction := range r.cassette.Interactions {
+		if !r.matcher(req, body, interaction.Request) {
+			continue
+		}
+
+		if !r.used[i] {
+			r.used[i] = true
+
+			return i, true
+		}
+
+		last = i
+	}
+
+	return last, last >= 0
+}
+
+// DefaultMatcher matches requests with the same method, path and query parameters, regardless of the host and of
+// the order of the parameters. Bodies match if they are equal JSON values, regardless of formatting and of the
+// order of object keys, or else if they are byte for byte equal.
+func DefaultMatcher(r *http.Request, body []byte, recorded Request) bool {
+	if r.Method != recorded.Method {
+		return false
+	}
+
+	u, err := url.Parse(recorded.URL)
+	if err != nil || r.URL.Path != u.Path || !reflect.DeepEqual(r.URL.Query(), u.Query()) {
+		return false
+	}
+
+	return equalBodies(body, []byte(recorded.Body))
+}
+
+// equalBodies compares bodies as JSON values if both are valid JSON, or byte for byte otherwise.
+func equalBodies(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) == nil && json.Unmarshal(b, &vb) == nil {
+		return reflect.DeepEqual(va, vb)
+	}
+
+	return bytes.Equal(a, b)
+}
+
+// readBody reads the request body and replaces it, so that the request can still be sent.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
+
+// response returns the recorded response as an http.Response to req.
+func (r Response) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,159 @@
+package vcr_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/leandrorondon/postcodesio-go"
+	"github.com/leandrorondon/postcodesio-go/vcr"
+	"github.com/stretchr/testify/assert"
+)
+
+// newServer returns a server answering postcode lookups and bulk lookups, counting the requests it gets.
+func newServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{"status":200,"result":{"postcode":%q}}`, strings.TrimPrefix(r.URL.Path, "/postcodes/"))
+
+			return
+		}
+
+		var req postcodesio.BulkPostCodeLookupRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		results := make([]string, len(req.Postcodes))
+		for i, p := range req.Postcodes {
+			results[i] = fmt.Sprintf(`{"query":%q,"result":{"postcode":%q}}`, p, p)
+		}
+
+		fmt.Fprintf(w, `{"status":200,"result":[%s]}`, strings.Join(results, ","))
+	}))
+}
+
+// failingTransport fails the test if a request is sent.
+type failingTransport struct{ t *testing.T }
+
+func (f failingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+
+	return nil, http.ErrHandlerTimeout
+}
+
+func TestRecorder(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := newServer(t, &requests)
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "testdata", "lookup.json")
+	ctx := context.Background()
+	bulk := postcodesio.BulkPostCodeLookupRequest{Postcodes: []string{"SW1A 2AA", "NW1 6XE"}}
+
+	rec, err := vcr.New(path, vcr.WithMode(vcr.ModeRecord))
+	assert.NoError(t, err)
+
+	c := postcodesio.New(postcodesio.WithBaseURL(srv.URL), postcodesio.WithTransport(rec))
+
+	res, err := c.PostcodeLookup(ctx, "SW1A2AA")
+	assert.NoError(t, err)
+	assert.Equal(t, "SW1A2AA", res.Result.Postcode)
+
+	bulkRes, err := c.BulkPostcodeLookup(ctx, bulk)
+	assert.NoError(t, err)
+	assert.Len(t, bulkRes.Result, 2)
+	assert.NoError(t, rec.Save())
+	assert.EqualValues(t, 2, requests.Load())
+
+	cassette, err := vcr.Load(path)
+	assert.NoError(t, err)
+	assert.Len(t, cassette.Interactions, 2)
+	assert.Equal(t, http.MethodPost, cassette.Interactions[1].Request.Method)
+	assert.JSONEq(t, `{"postcodes":["SW1A 2AA","NW1 6XE"]}`, cassette.Interactions[1].Request.Body)
+
+	rec, err = vcr.New(path, vcr.WithStrict(), vcr.WithTransport(failingTransport{t}))
+	assert.NoError(t, err)
+
+	c = postcodesio.New(postcodesio.WithBaseURL("http://replay.invalid"), postcodesio.WithTransport(rec))
+
+	replayed, err := c.PostcodeLookup(ctx, "SW1A2AA")
+	assert.NoError(t, err)
+	assert.Equal(t, res, replayed)
+
+	replayedBulk, err := c.BulkPostcodeLookup(ctx, bulk)
+	assert.NoError(t, err)
+	assert.Equal(t, bulkRes, replayedBulk)
+
+	_, err = c.BulkPostcodeLookup(ctx, postcodesio.BulkPostCodeLookupRequest{Postcodes: []string{"NW1 6XE", "SW1A 2AA"}})
+	assert.ErrorIs(t, err, vcr.ErrNotRecorded)
+
+	_, err = c.PostcodeLookup(ctx, "NW16XE")
+	assert.ErrorIs(t, err, vcr.ErrNotRecorded)
+	assert.EqualValues(t, 2, requests.Load())
+}
+
+func TestRecorder_ReplayRecordsMissing(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := newServer(t, &requests)
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "lookup.json")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		rec, err := vcr.New(path)
+		assert.NoError(t, err)
+
+		c := postcodesio.New(postcodesio.WithBaseURL(srv.URL), postcodesio.WithTransport(rec))
+
+		_, err = c.PostcodeLookup(ctx, "SW1A2AA")
+		assert.NoError(t, err)
+
+		_, err = c.PostcodeLookup(ctx, "SW1A2AA")
+		assert.NoError(t, err)
+		assert.NoError(t, rec.Save())
+	}
+
+	assert.EqualValues(t, 1, requests.Load())
+}
+
+func TestDefaultMatcher(t *testing.T) {
+	recorded := vcr.Request{
+		Method: http.MethodPost,
+		URL:    "https://api.postcodes.io/postcodes?filter=postcode,outcode&limit=5",
+		Body:   `{"postcodes":["SW1A 2AA"],"x":{"a":1,"b":2}}`,
+	}
+
+	tests := []struct {
+		method  string
+		url     string
+		body    string
+		matches bool
+	}{
+		{
+			http.MethodPost, "http://localhost:8000/postcodes?limit=5&filter=postcode,outcode",
+			`{"x":{"b":2,"a":1}, "postcodes": ["SW1A 2AA"]}`, true,
+		},
+		{http.MethodGet, "http://localhost:8000/postcodes?limit=5&filter=postcode,outcode", recorded.Body, false},
+		{http.MethodPost, "http://localhost:8000/postcodes?limit=6&filter=postcode,outcode", recorded.Body, false},
+		{http.MethodPost, "http://localhost:8000/outcodes?limit=5&filter=postcode,outcode", recorded.Body, false},
+		{http.MethodPost, "http://localhost:8000/postcodes?limit=5&filter=postcode,outcode", `{"postcodes":["NW1 6XE"]}`, false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.url, nil)
+		assert.Equal(t, tt.matches, vcr.DefaultMatcher(r, []byte(tt.body), recorded), tt)
+	}
+}